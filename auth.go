@@ -0,0 +1,224 @@
+package swagger_ring
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// AuthConfig restricts the documentation endpoints. The client IP must be in AllowedCIDRs when they are set,
+// and the request must carry valid basic-auth credentials or an API key when users or keys are set.
+type AuthConfig struct {
+	// Users are the basic-auth users.
+	Users []*AuthUser `json:"users"`
+	// APIKeyHeader is the header carrying the API key, X-API-Key by default.
+	APIKeyHeader string `json:"apiKeyHeader"`
+	// APIKeys are the accepted API keys.
+	APIKeys []string `json:"apiKeys"`
+	// AllowedCIDRs are the networks the clients may connect from, e.g. 10.0.0.0/8.
+	AllowedCIDRs []string `json:"allowedCidrs"`
+	// TrustedProxies are the networks whose X-Forwarded-For header is trusted to find the client IP.
+	TrustedProxies []string `json:"trustedProxies"`
+}
+
+// AuthUser is a basic-auth user. Password is a bcrypt hash as written by `htpasswd -B` (`$2y$...`),
+// or an unsalted `sha256:<hex>` or `sha512:<hex>` hash, which is fast to brute-force and kept for compatibility.
+type AuthUser struct {
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+const defaultAPIKeyHeader = "X-API-Key"
+
+// authenticator checks the requests of the documentation endpoints.
+type authenticator struct {
+	realm          string
+	users          map[string]passwordHash
+	apiKeyHeader   string
+	apiKeys        []string
+	allowedCIDRs   []*net.IPNet
+	trustedProxies []*net.IPNet
+}
+
+// passwordHash reports whether the password matches the hash of a user.
+type passwordHash func(password []byte) bool
+
+var passwordHashes = map[string]func([]byte) []byte{
+	"sha256": func(data []byte) []byte {
+		sum := sha256.Sum256(data)
+		return sum[:]
+	},
+	"sha512": func(data []byte) []byte {
+		sum := sha512.Sum512(data)
+		return sum[:]
+	},
+}
+
+// parsePasswordHash parses a bcrypt hash (`$2a$`, `$2b$` or `$2y$`) or a `sha256:<hex>` or `sha512:<hex>` hash.
+func parsePasswordHash(password string) (passwordHash, error) {
+	if strings.HasPrefix(password, "$2") {
+		hash := []byte(password)
+		if _, err := bcrypt.Cost(hash); err != nil {
+			return nil, fmt.Errorf("invalid bcrypt password hash: %w", err)
+		}
+		return func(password []byte) bool {
+			return bcrypt.CompareHashAndPassword(hash, password) == nil
+		}, nil
+	}
+	algorithm, sum, _ := strings.Cut(password, ":")
+	hash, ok := passwordHashes[algorithm]
+	if !ok {
+		return nil, fmt.Errorf("expected a bcrypt hash, sha256:<hex> or sha512:<hex>")
+	}
+	decoded, err := hex.DecodeString(sum)
+	if err != nil || len(decoded) != len(hash(nil)) {
+		return nil, fmt.Errorf("invalid %v password hash", algorithm)
+	}
+	return func(password []byte) bool {
+		return subtle.ConstantTimeCompare(hash(password), decoded) == 1
+	}, nil
+}
+
+// newAuthenticator parses the configuration. It returns nil when the endpoints are not restricted.
+func newAuthenticator(config *AuthConfig, realm string) (*authenticator, error) {
+	if config == nil {
+		return nil, nil
+	}
+	auth := &authenticator{realm: realm, users: make(map[string]passwordHash), apiKeyHeader: config.APIKeyHeader, apiKeys: config.APIKeys}
+	if auth.apiKeyHeader == "" {
+		auth.apiKeyHeader = defaultAPIKeyHeader
+	}
+	for _, user := range config.Users {
+		if user.Name == "" || strings.Contains(user.Name, ":") {
+			return nil, fmt.Errorf("invalid user name %q", user.Name)
+		}
+		if _, ok := auth.users[user.Name]; ok {
+			return nil, fmt.Errorf("duplicated user %v", user.Name)
+		}
+		hash, err := parsePasswordHash(user.Password)
+		if err != nil {
+			return nil, fmt.Errorf("invalid password of %v: %w", user.Name, err)
+		}
+		auth.users[user.Name] = hash
+	}
+	for _, key := range config.APIKeys {
+		if key == "" {
+			return nil, fmt.Errorf("api key cannot be empty")
+		}
+	}
+	var err error
+	if auth.allowedCIDRs, err = parseCIDRs(config.AllowedCIDRs); err != nil {
+		return nil, fmt.Errorf("invalid allowedCidrs: %w", err)
+	}
+	if auth.trustedProxies, err = parseCIDRs(config.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid trustedProxies: %w", err)
+	}
+	return auth, nil
+}
+
+// parseCIDRs parses networks, a single IP is a network of one address.
+func parseCIDRs(cidrs []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		if !strings.Contains(cidr, "/") {
+			ip := net.ParseIP(cidr)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP %q", cidr)
+			}
+			bits := 8 * len(ip.To16())
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+func containsIP(networks []*net.IPNet, ip net.IP) bool {
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIP returns the IP of the client. X-Forwarded-For is only read when the request comes from
+// a trusted proxy, and the first address from the right that is not a trusted proxy is the client.
+func (auth *authenticator) clientIP(req *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !containsIP(auth.trustedProxies, ip) {
+		return ip
+	}
+	forwarded := strings.Split(strings.Join(req.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop := net.ParseIP(strings.TrimSpace(forwarded[i]))
+		if hop == nil {
+			break
+		}
+		ip = hop
+		if !containsIP(auth.trustedProxies, hop) {
+			break
+		}
+	}
+	return ip
+}
+
+// authenticated tells whether the request carries valid basic-auth credentials or an API key.
+func (auth *authenticator) authenticated(req *http.Request) bool {
+	if len(auth.users) == 0 && len(auth.apiKeys) == 0 {
+		return true
+	}
+	if name, password, ok := req.BasicAuth(); ok {
+		if matches, ok := auth.users[name]; ok && matches([]byte(password)) {
+			return true
+		}
+	}
+	if key := req.Header.Get(auth.apiKeyHeader); key != "" {
+		for _, apiKey := range auth.apiKeys {
+			if subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) == 1 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// authorize checks the request and writes the rejection. It returns false when the request is rejected.
+func (auth *authenticator) authorize(rw http.ResponseWriter, req *http.Request) bool {
+	if auth == nil {
+		return true
+	}
+	if len(auth.allowedCIDRs) > 0 {
+		if ip := auth.clientIP(req); ip == nil || !containsIP(auth.allowedCIDRs, ip) {
+			http.Error(rw, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return false
+		}
+	}
+	if !auth.authenticated(req) {
+		if len(auth.users) > 0 {
+			rw.Header().Set("WWW-Authenticate", fmt.Sprintf("Basic realm=%q", auth.realm))
+		}
+		http.Error(rw, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+package swagger_ring_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	swagger "github.com/usalko/swagger-ring"
+)
+
+func TestAuth(t *testing.T) {
+	ctx := context.Background()
+
+	source := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(rw, "openapi: 3.0.2\ninfo:\n  title: Orders\n  version: 1.0.0\npaths: {}\n")
+	}))
+	defer source.Close()
+
+	cfg := swagger.CreateConfig()
+	cfg.Path = "/api/v1/docs"
+	cfg.Docs = append(cfg.Docs, &swagger.DocPath{Path: source.URL + "/swagger.yaml"})
+	cfg.Auth = &swagger.AuthConfig{
+		Users: []*swagger.AuthUser{
+			// sha256 of "secret"
+			{Name: "docs", Password: "sha256:2bb80d537b1da3e38bd30361aa855686bde0eacd7162fef6a25fe97bf527a25b"},
+			// htpasswd -nbB -C 5 ops "open sesame"
+			{Name: "ops", Password: "$2y$05$YTYG510z0MqKMSEQfZ51iOXpp3mDyh58DqPkTir.ZeU7o6oTrilWK"},
+		},
+		APIKeys:        []string{"key-1"},
+		AllowedCIDRs:   []string{"10.0.0.0/8"},
+		TrustedProxies: []string{"192.168.0.1"},
+	}
+
+	handler, err := swagger.New(ctx, http.NotFoundHandler(), cfg, "swagger-ring")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tt := []struct {
+		name      string
+		path      string
+		remote    string
+		forwarded string
+		user      string
+		password  string
+		apiKey    string
+		status    int
+	}{
+		{name: "basic auth", path: "/api/v1/docs/swagger.yaml", remote: "10.0.0.1:1234", user: "docs", password: "secret", status: http.StatusOK},
+		{name: "bcrypt", path: "/api/v1/docs/swagger.yaml", remote: "10.0.0.1:1234", user: "ops", password: "open sesame", status: http.StatusOK},
+		{name: "wrong bcrypt password", path: "/api/v1/docs/swagger.yaml", remote: "10.0.0.1:1234", user: "ops", password: "secret", status: http.StatusUnauthorized},
+		{name: "api key", path: "/api/v1/docs/swagger.json", remote: "10.0.0.1:1234", apiKey: "key-1", status: http.StatusOK},
+		{name: "wrong password", path: "/api/v1/docs/swagger.yaml", remote: "10.0.0.1:1234", user: "docs", password: "guess", status: http.StatusUnauthorized},
+		{name: "wrong api key", path: "/api/v1/docs", remote: "10.0.0.1:1234", apiKey: "key-2", status: http.StatusUnauthorized},
+		{name: "no credentials", path: "/api/v1/docs", remote: "10.0.0.1:1234", status: http.StatusUnauthorized},
+		{name: "denied ip", path: "/api/v1/docs/swagger.yaml", remote: "172.16.0.1:1234", apiKey: "key-1", status: http.StatusForbidden},
+		{name: "trusted proxy", path: "/api/v1/docs/swagger.yaml", remote: "192.168.0.1:1234", forwarded: "172.16.0.1, 10.0.0.2", apiKey: "key-1", status: http.StatusOK},
+		{name: "spoofed forwarded for", path: "/api/v1/docs/swagger.yaml", remote: "172.16.0.1:1234", forwarded: "10.0.0.2", apiKey: "key-1", status: http.StatusForbidden},
+		{name: "other path", path: "/api/v1/orders", remote: "172.16.0.1:1234", status: http.StatusNotFound},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tc.path, nil)
+			req.RemoteAddr = tc.remote
+			if tc.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tc.forwarded)
+			}
+			if tc.user != "" {
+				req.SetBasicAuth(tc.user, tc.password)
+			}
+			if tc.apiKey != "" {
+				req.Header.Set("X-API-Key", tc.apiKey)
+			}
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+			if rw.Code != tc.status {
+				t.Errorf("expected status %d, got %d", tc.status, rw.Code)
+			}
+			if tc.status == http.StatusUnauthorized && rw.Header().Get("WWW-Authenticate") == "" {
+				t.Error("expected WWW-Authenticate header")
+			}
+		})
+	}
+
+	cfg.Auth.Users[1].Password = "$2y$05$truncated"
+	if _, err := swagger.New(ctx, http.NotFoundHandler(), cfg, "swagger-ring"); err == nil {
+		t.Error("expected error for an invalid bcrypt hash, got nil")
+	}
+	cfg.Auth.Users[0].Password = "secret"
+	if _, err := swagger.New(ctx, http.NotFoundHandler(), cfg, "swagger-ring"); err == nil {
+		t.Error("expected error for a plain text password, got nil")
+	}
+}
@@ -0,0 +1,81 @@
+package swagger_ring
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// documentCache keeps rendered documents, and the merged document they are rendered from, for a limited time.
+// Concurrent misses of a key wait for a single build.
+type documentCache struct {
+	ttl      time.Duration
+	mutex    sync.Mutex
+	entries  map[string]cachedDocument
+	building map[string]*cacheBuild
+	metrics  *metrics
+}
+
+type cachedDocument struct {
+	value   any
+	expires time.Time
+}
+
+// cacheBuild is a build in flight, done is closed once value and err are set.
+type cacheBuild struct {
+	done  chan struct{}
+	value any
+	err   error
+}
+
+func newDocumentCache(ttl time.Duration, collector *metrics) *documentCache {
+	return &documentCache{ttl: ttl, entries: make(map[string]cachedDocument), building: make(map[string]*cacheBuild), metrics: collector}
+}
+
+// get returns the cached document for the key or builds and stores a new one.
+// Errors are never cached.
+func (cache *documentCache) get(key string, build func() (string, error)) (string, error) {
+	value, err := cache.getValue(key, func() (any, error) {
+		content, err := build()
+		return content, err
+	})
+	if err != nil {
+		return "", err
+	}
+	return value.(string), nil
+}
+
+// getValue is get for values other than rendered documents.
+func (cache *documentCache) getValue(key string, build func() (any, error)) (any, error) {
+	if cache.ttl <= 0 {
+		return build()
+	}
+	document, _, _ := strings.Cut(key, ".")
+	cache.mutex.Lock()
+	if entry, ok := cache.entries[key]; ok && time.Now().Before(entry.expires) {
+		cache.mutex.Unlock()
+		cache.metrics.inc(cache.metrics.cacheHits, document)
+		return entry.value, nil
+	}
+	if pending, ok := cache.building[key]; ok {
+		cache.mutex.Unlock()
+		<-pending.done
+		return pending.value, pending.err
+	}
+	pending := &cacheBuild{done: make(chan struct{})}
+	cache.building[key] = pending
+	cache.mutex.Unlock()
+	cache.metrics.inc(cache.metrics.cacheMisses, document)
+
+	defer func() {
+		cache.mutex.Lock()
+		delete(cache.building, key)
+		if pending.err == nil {
+			cache.entries[key] = cachedDocument{value: pending.value, expires: time.Now().Add(cache.ttl)}
+		}
+		cache.mutex.Unlock()
+		close(pending.done)
+	}()
+	pending.value, pending.err = build()
+	return pending.value, pending.err
+}
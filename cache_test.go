@@ -0,0 +1,54 @@
+package swagger_ring_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	swagger "github.com/usalko/swagger-ring"
+)
+
+func TestCacheConcurrentMisses(t *testing.T) {
+	ctx := context.Background()
+
+	var fetches atomic.Int32
+	source := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fetches.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		fmt.Fprint(rw, "openapi: 3.0.2\ninfo:\n  title: Orders\n  version: 1.0.0\npaths: {}\n")
+	}))
+	defer source.Close()
+
+	cfg := swagger.CreateConfig()
+	cfg.Path = "/api/v1/docs"
+	cfg.CacheTTL = "1m"
+	cfg.Docs = append(cfg.Docs, &swagger.DocPath{Path: source.URL + "/swagger.yaml"})
+
+	handler, err := swagger.New(ctx, http.NotFoundHandler(), cfg, "swagger-ring")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, httptest.NewRequest("GET", "/api/v1/docs/swagger.yaml", nil))
+			if !strings.Contains(rw.Body.String(), "title: Orders") {
+				t.Errorf("expected the merged document, got %s", rw.Body.String())
+			}
+		}()
+	}
+	wg.Wait()
+	if fetches.Load() != 1 {
+		t.Errorf("expected the concurrent misses to share one build, got %d fetches", fetches.Load())
+	}
+}
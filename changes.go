@@ -0,0 +1,317 @@
+package swagger_ring
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SpecChange is a single difference between two versions of the merged document.
+type SpecChange struct {
+	// Pointer is the JSON pointer of the changed element.
+	Pointer string `json:"pointer"`
+	// Kind is a short identifier of the change, e.g. operation-removed.
+	Kind string `json:"kind"`
+	// Breaking reports whether existing clients may stop working.
+	Breaking bool   `json:"breaking"`
+	Message  string `json:"message"`
+}
+
+// ChangeReport lists the differences between two successive versions of the merged document.
+type ChangeReport struct {
+	// From is the content hash of the previous version.
+	From string `json:"from"`
+	// To is the content hash of the current version.
+	To       string        `json:"to"`
+	Time     time.Time     `json:"time"`
+	Breaking int           `json:"breaking"`
+	Changes  []*SpecChange `json:"changes"`
+}
+
+// specTracker remembers the last version of the merged document to detect changes.
+type specTracker struct {
+	mutex    sync.Mutex
+	hash     string
+	document map[string]any
+	changes  *ChangeReport
+	history  *specHistory
+}
+
+// documentHash returns the content hash of the document.
+func documentHash(document map[string]any) string {
+	// encoding/json sorts map keys, so equal documents give equal hashes
+	data, _ := json.Marshal(document)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// observe records the document and returns the change report when it differs from the previous version.
+func (tracker *specTracker) observe(document map[string]any) *ChangeReport {
+	hash := documentHash(document)
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	if hash == tracker.hash {
+		return nil
+	}
+	previous, previousHash := tracker.document, tracker.hash
+	tracker.hash, tracker.document = hash, deepCopy(document).(map[string]any)
+	tracker.history.add(hash, tracker.document)
+	if previous == nil {
+		return nil
+	}
+	report := &ChangeReport{From: previousHash, To: hash, Time: time.Now().UTC()}
+	report.Changes = diffDocuments(previous, tracker.document)
+	for _, change := range report.Changes {
+		if change.Breaking {
+			report.Breaking++
+		}
+	}
+	tracker.changes = report
+	return report
+}
+
+// latest returns the report of the last detected change.
+func (tracker *specTracker) latest() *ChangeReport {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+	return tracker.changes
+}
+
+// specDiff compares two versions of an OpenAPI document.
+type specDiff struct {
+	base     map[string]any
+	revision map[string]any
+	changes  []*SpecChange
+	active   map[string]bool
+}
+
+// diffDocuments classifies the differences between the base and the revision documents.
+func diffDocuments(base, revision map[string]any) []*SpecChange {
+	diff := &specDiff{base: base, revision: revision, changes: make([]*SpecChange, 0), active: make(map[string]bool)}
+	basePaths, _ := base["paths"].(map[string]any)
+	revisionPaths, _ := revision["paths"].(map[string]any)
+	for _, path := range sortedKeys(basePaths) {
+		pathPointer := joinPointer("/paths", path)
+		baseItem, _ := basePaths[path].(map[string]any)
+		revisionItem, exists := revisionPaths[path].(map[string]any)
+		for _, method := range httpMethods {
+			baseOperation, ok := baseItem[method].(map[string]any)
+			if !ok {
+				continue
+			}
+			operationPointer := joinPointer(pathPointer, method)
+			revisionOperation, ok := revisionItem[method].(map[string]any)
+			if !exists || !ok {
+				diff.add(operationPointer, "operation-removed", true, "operation %v %v was removed", strings.ToUpper(method), path)
+				continue
+			}
+			diff.compareOperation(operationPointer, baseItem, baseOperation, revisionItem, revisionOperation)
+		}
+	}
+	for _, path := range sortedKeys(revisionPaths) {
+		revisionItem, _ := revisionPaths[path].(map[string]any)
+		baseItem, _ := basePaths[path].(map[string]any)
+		for _, method := range httpMethods {
+			if _, ok := revisionItem[method].(map[string]any); !ok {
+				continue
+			}
+			if _, ok := baseItem[method].(map[string]any); !ok {
+				diff.add(joinPointer(joinPointer("/paths", path), method), "operation-added", false, "operation %v %v was added", strings.ToUpper(method), path)
+			}
+		}
+	}
+	sort.SliceStable(diff.changes, func(i, j int) bool {
+		return comparePointers(diff.changes[i].Pointer, diff.changes[j].Pointer) < 0
+	})
+	return diff.changes
+}
+
+func (diff *specDiff) add(pointer, kind string, breaking bool, format string, args ...any) {
+	diff.changes = append(diff.changes, &SpecChange{
+		Pointer:  pointer,
+		Kind:     kind,
+		Breaking: breaking,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (diff *specDiff) compareOperation(pointer string, baseItem, baseOperation, revisionItem, revisionOperation map[string]any) {
+	baseParameters := operationParameters(diff.base, pointer, baseItem, baseOperation)
+	revisionParameters := operationParameters(diff.revision, pointer, revisionItem, revisionOperation)
+	for _, key := range sortedParameterKeys(baseParameters) {
+		base := baseParameters[key]
+		revision, ok := revisionParameters[key]
+		if !ok {
+			diff.add(base.pointer, "parameter-removed", false, "parameter %v was removed", key)
+			continue
+		}
+		if revision.parameter["required"] == true && base.parameter["required"] != true {
+			diff.add(revision.pointer, "parameter-became-required", true, "parameter %v became required", key)
+		}
+		diff.compareSchemas(joinPointer(revision.pointer, "schema"), base.parameter["schema"], revision.parameter["schema"], true)
+	}
+	for _, key := range sortedParameterKeys(revisionParameters) {
+		if _, ok := baseParameters[key]; ok {
+			continue
+		}
+		revision := revisionParameters[key]
+		if revision.parameter["required"] == true {
+			diff.add(revision.pointer, "required-parameter-added", true, "required parameter %v was added", key)
+		} else {
+			diff.add(revision.pointer, "parameter-added", false, "optional parameter %v was added", key)
+		}
+	}
+
+	baseBody, _ := resolve(diff.base, baseOperation["requestBody"])
+	revisionBody, _ := resolve(diff.revision, revisionOperation["requestBody"])
+	bodyPointer := joinPointer(pointer, "requestBody")
+	switch {
+	case baseBody == nil && revisionBody != nil && revisionBody["required"] == true:
+		diff.add(bodyPointer, "required-request-body-added", true, "required request body was added")
+	case baseBody != nil && revisionBody != nil:
+		if revisionBody["required"] == true && baseBody["required"] != true {
+			diff.add(bodyPointer, "request-body-became-required", true, "request body became required")
+		}
+		diff.compareContent(joinPointer(bodyPointer, "content"), baseBody["content"], revisionBody["content"], true)
+	}
+
+	baseResponses, _ := baseOperation["responses"].(map[string]any)
+	revisionResponses, _ := revisionOperation["responses"].(map[string]any)
+	responsesPointer := joinPointer(pointer, "responses")
+	for _, code := range sortedKeys(baseResponses) {
+		revisionValue, ok := revisionResponses[code]
+		if !ok {
+			diff.add(joinPointer(responsesPointer, code), "response-removed", true, "response %v was removed", code)
+			continue
+		}
+		baseResponse, _ := resolve(diff.base, baseResponses[code])
+		revisionResponse, _ := resolve(diff.revision, revisionValue)
+		if baseResponse != nil && revisionResponse != nil {
+			diff.compareContent(joinPointer(joinPointer(responsesPointer, code), "content"), baseResponse["content"], revisionResponse["content"], false)
+		}
+	}
+	for _, code := range sortedKeys(revisionResponses) {
+		if _, ok := baseResponses[code]; !ok {
+			diff.add(joinPointer(responsesPointer, code), "response-added", false, "response %v was added", code)
+		}
+	}
+}
+
+func (diff *specDiff) compareContent(pointer string, baseValue, revisionValue any, request bool) {
+	baseContent, _ := baseValue.(map[string]any)
+	revisionContent, _ := revisionValue.(map[string]any)
+	for _, mediaType := range sortedKeys(baseContent) {
+		mediaPointer := joinPointer(pointer, mediaType)
+		revisionMedia, ok := revisionContent[mediaType].(map[string]any)
+		if !ok {
+			diff.add(mediaPointer, "media-type-removed", true, "media type %v was removed", mediaType)
+			continue
+		}
+		baseMedia, _ := baseContent[mediaType].(map[string]any)
+		diff.compareSchemas(joinPointer(mediaPointer, "schema"), baseMedia["schema"], revisionMedia["schema"], request)
+	}
+	for _, mediaType := range sortedKeys(revisionContent) {
+		if _, ok := baseContent[mediaType]; !ok {
+			diff.add(joinPointer(pointer, mediaType), "media-type-added", false, "media type %v was added", mediaType)
+		}
+	}
+}
+
+// compareSchemas compares two schemas used in a request (clients send values) or in a response (clients read values).
+func (diff *specDiff) compareSchemas(pointer string, baseValue, revisionValue any, request bool) {
+	baseSchema, baseRef := resolve(diff.base, baseValue)
+	revisionSchema, revisionRef := resolve(diff.revision, revisionValue)
+	if baseSchema == nil || revisionSchema == nil {
+		return
+	}
+	if baseRef != "" || revisionRef != "" {
+		// Stop at recursive schemas
+		key := baseRef + "|" + revisionRef
+		if diff.active[key] {
+			return
+		}
+		diff.active[key] = true
+		defer delete(diff.active, key)
+	}
+
+	if baseType, revisionType := baseSchema["type"], revisionSchema["type"]; baseType != nil && revisionType != nil && fmt.Sprint(baseType) != fmt.Sprint(revisionType) {
+		diff.add(pointer, "type-changed", true, "type changed from %v to %v", baseType, revisionType)
+		return
+	}
+
+	// Narrowed values break clients sending requests, widened values break clients reading responses
+	baseEnum, revisionEnum := stringSet(baseSchema["enum"]), stringSet(revisionSchema["enum"])
+	switch {
+	case len(baseEnum) == 0 && len(revisionEnum) > 0:
+		diff.add(joinPointer(pointer, "enum"), "enum-added", request, "values were limited to %v", sortedSetKeys(revisionEnum))
+	case len(baseEnum) > 0 && len(revisionEnum) == 0:
+		diff.add(pointer, "enum-removed", !request, "values are no longer limited to %v", sortedSetKeys(baseEnum))
+	default:
+		for _, value := range sortedSetKeys(baseEnum) {
+			if !revisionEnum[value] {
+				diff.add(joinPointer(pointer, "enum"), "enum-value-removed", request, "enum value %v was removed", value)
+			}
+		}
+		for _, value := range sortedSetKeys(revisionEnum) {
+			if !baseEnum[value] {
+				diff.add(joinPointer(pointer, "enum"), "enum-value-added", !request, "enum value %v was added", value)
+			}
+		}
+	}
+
+	baseRequired, revisionRequired := stringSet(baseSchema["required"]), stringSet(revisionSchema["required"])
+	for _, name := range sortedSetKeys(revisionRequired) {
+		if !baseRequired[name] && request {
+			diff.add(joinPointer(pointer, "required"), "property-became-required", true, "property %v became required", name)
+		}
+	}
+	for _, name := range sortedSetKeys(baseRequired) {
+		if !revisionRequired[name] && !request {
+			diff.add(joinPointer(pointer, "required"), "property-became-optional", true, "response property %v became optional", name)
+		}
+	}
+
+	baseProperties, _ := baseSchema["properties"].(map[string]any)
+	revisionProperties, _ := revisionSchema["properties"].(map[string]any)
+	for _, name := range sortedKeys(baseProperties) {
+		propertyPointer := joinPointer(joinPointer(pointer, "properties"), name)
+		revisionProperty, ok := revisionProperties[name]
+		if !ok {
+			diff.add(propertyPointer, "property-removed", !request, "property %v was removed", name)
+			continue
+		}
+		diff.compareSchemas(propertyPointer, baseProperties[name], revisionProperty, request)
+	}
+	for _, name := range sortedKeys(revisionProperties) {
+		if _, ok := baseProperties[name]; !ok {
+			diff.add(joinPointer(joinPointer(pointer, "properties"), name), "property-added", false, "property %v was added", name)
+		}
+	}
+	if baseSchema["items"] != nil && revisionSchema["items"] != nil {
+		diff.compareSchemas(joinPointer(pointer, "items"), baseSchema["items"], revisionSchema["items"], request)
+	}
+}
+
+// stringSet converts a list of values to a set of their string forms.
+func stringSet(value any) map[string]bool {
+	values, _ := value.([]any)
+	set := make(map[string]bool, len(values))
+	for _, element := range values {
+		set[fmt.Sprint(element)] = true
+	}
+	return set
+}
+
+func sortedSetKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
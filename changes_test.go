@@ -0,0 +1,119 @@
+package swagger_ring_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	swagger "github.com/usalko/swagger-ring"
+)
+
+const changesBase = `
+openapi: 3.0.2
+info:
+  title: Orders
+  version: 1.0.0
+paths:
+  /orders:
+    get:
+      parameters:
+        - name: status
+          in: query
+          schema:
+            type: string
+            enum: [new, paid, shipped]
+      responses:
+        200:
+          description: OK
+    delete:
+      responses:
+        204:
+          description: Deleted
+`
+
+const changesRevision = `
+openapi: 3.0.2
+info:
+  title: Orders
+  version: 1.1.0
+paths:
+  /orders:
+    get:
+      parameters:
+        - name: status
+          in: query
+          schema:
+            type: string
+            enum: [new, paid]
+        - name: tenant
+          in: header
+          required: true
+          schema:
+            type: string
+      responses:
+        200:
+          description: OK
+    post:
+      responses:
+        201:
+          description: Created
+`
+
+func TestChanges(t *testing.T) {
+	ctx := context.Background()
+
+	var revision atomic.Bool
+	source := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if revision.Load() {
+			fmt.Fprint(rw, changesRevision)
+			return
+		}
+		fmt.Fprint(rw, changesBase)
+	}))
+	defer source.Close()
+
+	cfg := swagger.CreateConfig()
+	cfg.Path = "/api/v1/docs"
+	cfg.Docs = append(cfg.Docs, &swagger.DocPath{Path: source.URL + "/swagger.yaml"})
+
+	handler, err := swagger.New(ctx, http.NotFoundHandler(), cfg, "swagger-ring")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if report := getJSON[*swagger.ChangeReport](t, handler, "/api/v1/docs/changes"); len(report.Changes) != 0 {
+		t.Errorf("expected no changes for the first version, got %+v", report.Changes)
+	}
+
+	revision.Store(true)
+	report := getJSON[*swagger.ChangeReport](t, handler, "/api/v1/docs/changes")
+	if report.From == "" || report.From == report.To {
+		t.Errorf("expected hashes of two versions, got %v and %v", report.From, report.To)
+	}
+	expected := map[string]bool{
+		"operation-removed":        true,
+		"enum-value-removed":       true,
+		"required-parameter-added": true,
+		"operation-added":          false,
+	}
+	for _, change := range report.Changes {
+		breaking, ok := expected[change.Kind]
+		if !ok {
+			t.Errorf("unexpected change %+v", change)
+			continue
+		}
+		if change.Breaking != breaking {
+			t.Errorf("expected %v to be breaking=%v", change.Kind, breaking)
+		}
+		delete(expected, change.Kind)
+	}
+	for kind := range expected {
+		t.Errorf("expected %v change", kind)
+	}
+	if report.Breaking != 3 {
+		t.Errorf("expected 3 breaking changes, got %d", report.Breaking)
+	}
+}
@@ -0,0 +1,153 @@
+// Command swagger-ring merges OpenAPI documents offline with the same engine as the Traefik plugin,
+// so CI can publish the merged document and catch merge problems before deployment.
+//
+// Usage:
+//
+//	swagger-ring merge [-config swagger-ring.yaml] [-format yaml|json] [-output file] [-dereference] [-strict=false] [document...]
+//
+// The documents are local files or URLs, merged after the docs of the configuration.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	swagger "github.com/usalko/swagger-ring"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+const usage = "usage: swagger-ring merge [-config file] [-format yaml|json] [-output file] [-dereference] [-strict=false] [document...]"
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] != "merge" {
+		fmt.Fprintln(stderr, usage)
+		return 2
+	}
+	flags := flag.NewFlagSet("merge", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	configFile := flags.String("config", "", "plugin configuration in YAML or JSON")
+	format := flags.String("format", "yaml", "format of the merged document, yaml or json")
+	output := flags.String("output", "", "file the merged document is written to, stdout by default")
+	dereference := flags.Bool("dereference", false, "inline every internal $ref")
+	strict := flags.Bool("strict", true, "fail when a document cannot be loaded or the merged OpenAPI 3.x document is invalid")
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	docType := swagger.DOC_TYPE_YAML
+	switch *format {
+	case "yaml":
+	case "json":
+		docType = swagger.DOC_TYPE_JSON
+	default:
+		fmt.Fprintf(stderr, "invalid format %q\n", *format)
+		return 2
+	}
+	config := swagger.CreateConfig()
+	if *configFile != "" {
+		data, err := os.ReadFile(*configFile)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		if config, err = swagger.ParseConfig(data); err != nil {
+			fmt.Fprintf(stderr, "%v: %v\n", *configFile, err)
+			return 1
+		}
+	}
+	for _, document := range flags.Args() {
+		config.Docs = append(config.Docs, &swagger.DocPath{Path: document})
+	}
+	if config.CacheTTL == "" {
+		// The reports below reuse the documents fetched for the merge
+		config.CacheTTL = "1h"
+	}
+
+	handler, err := swagger.New(context.Background(), http.NotFoundHandler(), config, "swagger-ring")
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	ring := handler.(*swagger.SwaggerRing)
+	getDocument := ring.GetMergedSwaggerDoc
+	if *dereference {
+		getDocument = ring.GetDereferencedSwaggerDoc
+	}
+	document, err := getDocument(docType)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	if *strict && !check(ring, stderr) {
+		return 1
+	}
+
+	if *output == "" {
+		fmt.Fprint(stdout, document)
+		return 0
+	}
+	if err := os.WriteFile(*output, []byte(document), 0o644); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// check reports the documents that could not be loaded and the validation errors of the merged document.
+// Swagger 2.0 documents are not validated.
+func check(ring *swagger.SwaggerRing, stderr io.Writer) bool {
+	ok := true
+	status := &swagger.StatusReport{}
+	if err := unmarshalReport(ring.GetStatusReport, status); err != nil {
+		fmt.Fprintln(stderr, err)
+		return false
+	}
+	for _, source := range status.Sources {
+		if source.Error != "" {
+			fmt.Fprintf(stderr, "%v: %v\n", source.URL, source.Error)
+			ok = false
+		}
+	}
+	// The structural validation only knows OpenAPI 3.x
+	version := &struct {
+		Swagger string `json:"swagger"`
+	}{}
+	if err := unmarshalReport(func() (string, error) { return ring.GetMergedSwaggerDoc(swagger.DOC_TYPE_JSON) }, version); err != nil {
+		fmt.Fprintln(stderr, err)
+		return false
+	}
+	if strings.HasPrefix(version.Swagger, "2.") {
+		return ok
+	}
+	validation := &swagger.ValidationReport{}
+	if err := unmarshalReport(ring.GetValidationReport, validation); err != nil {
+		fmt.Fprintln(stderr, err)
+		return false
+	}
+	for _, validationError := range validation.Errors {
+		if validationError.Source != "" {
+			fmt.Fprintf(stderr, "%v: %v (%v)\n", validationError.Pointer, validationError.Message, validationError.Source)
+		} else {
+			fmt.Fprintf(stderr, "%v: %v\n", validationError.Pointer, validationError.Message)
+		}
+		ok = false
+	}
+	return ok
+}
+
+func unmarshalReport(getReport func() (string, error), report any) error {
+	data, err := getReport()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(data), report)
+}
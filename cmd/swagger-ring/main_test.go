@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const usersSource = "openapi: 3.0.2\ninfo:\n  title: Users\n  version: 1.0.0\npaths:\n  /users:\n    get:\n      responses:\n        '200':\n          description: OK\n"
+
+const ordersSource = "openapi: 3.0.2\ninfo:\n  title: Orders\n  version: 1.0.0\npaths:\n  /orders:\n    get:\n      responses:\n        '200':\n          description: OK\n"
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	users := writeFile(t, dir, "users.yaml", usersSource)
+	orders := writeFile(t, dir, "orders.yaml", ordersSource)
+	config := writeFile(t, dir, "swagger-ring.yaml", "docs:\n  - path: "+users+"\n")
+	output := filepath.Join(dir, "openapi.yaml")
+
+	tt := []struct {
+		name     string
+		args     []string
+		code     int
+		stdout   string
+		stderr   string
+		output   string
+		document bool
+	}{
+		{name: "usage", args: nil, code: 2, stderr: "usage: swagger-ring merge"},
+		{name: "unknown command", args: []string{"split"}, code: 2, stderr: "usage: swagger-ring merge"},
+		{name: "invalid format", args: []string{"merge", "-format", "xml", users}, code: 2, stderr: `invalid format "xml"`},
+		{name: "yaml", args: []string{"merge", users, orders}, stdout: "title: Orders"},
+		{name: "json", args: []string{"merge", "-config", config, "-format", "json", orders}, document: true},
+		{name: "output", args: []string{"merge", "-output", output, users, orders}, output: "/users:"},
+		{name: "missing source", args: []string{"merge", users, filepath.Join(dir, "missing.yaml")}, code: 1, stderr: "missing.yaml"},
+		{name: "missing source not strict", args: []string{"merge", "-strict=false", users, filepath.Join(dir, "missing.yaml")}, stdout: "/users:"},
+		{name: "swagger 2.0", args: []string{"merge", filepath.Join("..", "..", "tests", "8083.yaml")}, stdout: "swagger: \"2.0\""},
+		{name: "swagger 2.0 missing source", args: []string{"merge", filepath.Join("..", "..", "tests", "8083.yaml"), filepath.Join(dir, "missing.yaml")}, code: 1, stderr: "missing.yaml"},
+		{name: "invalid document", args: []string{"merge", writeFile(t, dir, "invalid.yaml", "openapi: 3.0.2\ninfo:\n  title: Invalid\n  version: 1.0.0\npaths:\n  /users:\n    get: {}\n")}, code: 1, stderr: "/paths/~1users/get"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+			if code := run(tc.args, stdout, stderr); code != tc.code {
+				t.Fatalf("expected exit code %d, got %d: %s", tc.code, code, stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tc.stdout) {
+				t.Errorf("expected output to contain %s, got %s", tc.stdout, stdout.String())
+			}
+			if !strings.Contains(stderr.String(), tc.stderr) {
+				t.Errorf("expected errors to contain %s, got %s", tc.stderr, stderr.String())
+			}
+			if tc.document {
+				var document struct {
+					Paths map[string]any `json:"paths"`
+				}
+				if err := json.Unmarshal(stdout.Bytes(), &document); err != nil {
+					t.Fatalf("expected a JSON document, got %v", err)
+				}
+				if document.Paths["/users"] == nil || document.Paths["/orders"] == nil {
+					t.Errorf("expected the paths of the configured and the given documents, got %v", document.Paths)
+				}
+			}
+			if tc.output != "" {
+				data, err := os.ReadFile(output)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if stdout.Len() > 0 || !strings.Contains(string(data), tc.output) {
+					t.Errorf("expected the document in %v only, got %s and %s", output, data, stdout.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package swagger_ring_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	swagger "github.com/usalko/swagger-ring"
+)
+
+func TestParseConfig(t *testing.T) {
+	cfg, err := swagger.ParseConfig([]byte("path: /docs\ncacheTTL: 5m\ndocs:\n  - path: users.yaml\n    name: users\n"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cfg.Path != "/docs" || cfg.CacheTTL != "5m" || len(cfg.Docs) != 1 || cfg.Docs[0].Path != "users.yaml" || cfg.Docs[0].Name != "users" {
+		t.Errorf("unexpected configuration %+v", cfg)
+	}
+
+	if _, err := swagger.ParseConfig([]byte("docs: [")); err == nil {
+		t.Error("expected an error for malformed YAML")
+	}
+	if _, err := swagger.ParseConfig([]byte("docs: users.yaml")); err == nil {
+		t.Error("expected an error for a wrong docs type")
+	}
+}
+
+func TestLocalDocuments(t *testing.T) {
+	dir := t.TempDir()
+	users := filepath.Join(dir, "users.yaml")
+	orders := filepath.Join(dir, "orders.json")
+	if err := os.WriteFile(users, []byte("openapi: 3.0.2\ninfo:\n  title: users\n  version: 1.0.0\npaths:\n  /users:\n    get:\n      responses:\n        '200':\n          description: ok\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(orders, []byte(`{"openapi":"3.0.2","info":{"title":"orders","version":"1.0.0"},"paths":{"/orders":{"get":{"responses":{"200":{"description":"ok"}}}}}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := swagger.CreateConfig()
+	cfg.Docs = append(cfg.Docs, &swagger.DocPath{Path: users}, &swagger.DocPath{Path: "file://" + orders})
+	handler, err := swagger.New(context.Background(), http.NotFoundHandler(), cfg, "swagger-ring")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	document, err := handler.(*swagger.SwaggerRing).GetMergedSwaggerDoc(swagger.DOC_TYPE_YAML)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for _, path := range []string{"/users:", "/orders:"} {
+		if !strings.Contains(document, path) {
+			t.Errorf("expected merged document to contain %s, got %s", path, document)
+		}
+	}
+}
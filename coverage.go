@@ -0,0 +1,228 @@
+package swagger_ring
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CoverageConfig configures the documentation coverage recorded from the API traffic.
+type CoverageConfig struct {
+	// MaxUndocumented is the number of distinct undocumented routes kept, 1000 by default.
+	MaxUndocumented int `json:"maxUndocumented"`
+}
+
+// OperationUsage is the traffic of an operation or an undocumented route.
+type OperationUsage struct {
+	Method   string     `json:"method"`
+	Path     string     `json:"path"`
+	Count    int64      `json:"count"`
+	LastSeen *time.Time `json:"lastSeen,omitempty"`
+}
+
+// SourceCoverage is the coverage of the operations of a source.
+type SourceCoverage struct {
+	Source     string `json:"source"`
+	Operations int    `json:"operations"`
+	Used       int    `json:"used"`
+	// Unused are the documented operations that received no request.
+	Unused []*OperationUsage `json:"unused"`
+	// UsedOperations are the documented operations that received requests.
+	UsedOperations []*OperationUsage `json:"usedOperations"`
+	// Undocumented are the routes that received requests without a documented operation,
+	// attributed to the source whose paths are the closest.
+	Undocumented []*OperationUsage `json:"undocumented"`
+}
+
+// CoverageReport is the documentation coverage by source.
+type CoverageReport struct {
+	Since   time.Time         `json:"since"`
+	Sources []*SourceCoverage `json:"sources"`
+}
+
+const defaultMaxUndocumented = 1000
+
+// identifierSegment matches the path segments that are identifiers, grouped in the undocumented routes.
+var identifierSegment = regexp.MustCompile(`^([0-9]+|[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|[0-9a-fA-F]{24,})$`)
+
+// coverageRecorder counts the requests by operation and by undocumented route.
+type coverageRecorder struct {
+	mutex           sync.Mutex
+	since           time.Time
+	maxUndocumented int
+	// operations are keyed by source, method and path template
+	operations map[string]*OperationUsage
+	// undocumented are keyed by source, method and path
+	undocumented map[string]*undocumentedRoute
+}
+
+type undocumentedRoute struct {
+	source string
+	usage  *OperationUsage
+}
+
+func newCoverageRecorder(config *CoverageConfig) *coverageRecorder {
+	if config == nil {
+		return nil
+	}
+	recorder := &coverageRecorder{
+		since:           time.Now().UTC(),
+		maxUndocumented: config.MaxUndocumented,
+		operations:      make(map[string]*OperationUsage),
+		undocumented:    make(map[string]*undocumentedRoute),
+	}
+	if recorder.maxUndocumented <= 0 {
+		recorder.maxUndocumented = defaultMaxUndocumented
+	}
+	return recorder
+}
+
+func coverageKey(source, method, path string) string {
+	return source + "\x00" + method + " " + path
+}
+
+// record counts the request of the matched operation, or of the undocumented route when match is nil.
+func (recorder *coverageRecorder) record(req *http.Request, match *operationMatch, routes []*operationRoute) {
+	now := time.Now().UTC()
+	if match != nil {
+		recorder.mutex.Lock()
+		defer recorder.mutex.Unlock()
+		key := coverageKey(match.source, match.method, match.path)
+		usage, ok := recorder.operations[key]
+		if !ok {
+			usage = &OperationUsage{Method: match.method, Path: match.path}
+			recorder.operations[key] = usage
+		}
+		usage.Count++
+		usage.LastSeen = &now
+		return
+	}
+
+	path := undocumentedPath(req.URL.Path)
+	source := closestSource(req.URL.Path, routes)
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	key := coverageKey(source, req.Method, path)
+	route, ok := recorder.undocumented[key]
+	if !ok {
+		if len(recorder.undocumented) >= recorder.maxUndocumented {
+			return
+		}
+		route = &undocumentedRoute{source: source, usage: &OperationUsage{Method: req.Method, Path: path}}
+		recorder.undocumented[key] = route
+	}
+	route.usage.Count++
+	route.usage.LastSeen = &now
+}
+
+// undocumentedPath groups the identifiers of the path, /orders/42 is recorded as /orders/{id}.
+func undocumentedPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if identifierSegment.MatchString(segment) {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// closestSource returns the source of the operation whose path shares the most leading segments with the path.
+func closestSource(path string, routes []*operationRoute) string {
+	source, best := "", 0
+	for _, route := range routes {
+		for _, prefix := range route.prefixes {
+			if prefix != "" && !strings.HasPrefix(path, prefix+"/") {
+				continue
+			}
+			if common := commonSegments(strings.TrimPrefix(path, prefix), route.path); common > best {
+				source, best = route.source, common
+			}
+		}
+	}
+	return source
+}
+
+func commonSegments(path, template string) int {
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	templateSegments := strings.Split(strings.Trim(template, "/"), "/")
+	common := 0
+	for common < len(pathSegments) && common < len(templateSegments) {
+		segment := templateSegments[common]
+		if segment != pathSegments[common] && !strings.HasPrefix(segment, "{") || segment == "" {
+			break
+		}
+		common++
+	}
+	return common
+}
+
+// report returns the coverage of the operations of the routes by source.
+func (recorder *coverageRecorder) report(routes []*operationRoute, refs []DocPath) *CoverageReport {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	report := &CoverageReport{Since: recorder.since, Sources: make([]*SourceCoverage, 0, len(refs)+1)}
+	sources := make(map[string]*SourceCoverage, len(refs))
+	coverageOf := func(source string) *SourceCoverage {
+		coverage, ok := sources[source]
+		if !ok {
+			coverage = &SourceCoverage{
+				Source:         source,
+				Unused:         make([]*OperationUsage, 0),
+				UsedOperations: make([]*OperationUsage, 0),
+				Undocumented:   make([]*OperationUsage, 0),
+			}
+			sources[source] = coverage
+			report.Sources = append(report.Sources, coverage)
+		}
+		return coverage
+	}
+	for _, ref := range refs {
+		coverageOf(ref.Name)
+	}
+
+	for _, route := range routes {
+		coverage := coverageOf(route.source)
+		coverage.Operations++
+		if usage, ok := recorder.operations[coverageKey(route.source, route.method, route.path)]; ok {
+			coverage.Used++
+			copied := *usage
+			coverage.UsedOperations = append(coverage.UsedOperations, &copied)
+			continue
+		}
+		coverage.Unused = append(coverage.Unused, &OperationUsage{Method: route.method, Path: route.path})
+	}
+	for _, route := range recorder.undocumented {
+		coverage := coverageOf(route.source)
+		copied := *route.usage
+		coverage.Undocumented = append(coverage.Undocumented, &copied)
+	}
+	for _, coverage := range report.Sources {
+		for _, usages := range [][]*OperationUsage{coverage.Unused, coverage.UsedOperations, coverage.Undocumented} {
+			sort.Slice(usages, func(i, j int) bool {
+				if usages[i].Path != usages[j].Path {
+					return usages[i].Path < usages[j].Path
+				}
+				return usages[i].Method < usages[j].Method
+			})
+		}
+	}
+	return report
+}
+
+// GetCoverageReport returns the documentation coverage recorded from the API traffic as JSON.
+func (swaggerMerger *SwaggerRing) GetCoverageReport() (string, error) {
+	if swaggerMerger.coverage == nil {
+		return "", fmt.Errorf("coverage is not enabled")
+	}
+	routes, _ := swaggerMerger.operationRoutes()
+	report, err := json.Marshal(swaggerMerger.coverage.report(routes, swaggerMerger.refs))
+	if err != nil {
+		return "", err
+	}
+	return string(report), nil
+}
@@ -0,0 +1,81 @@
+package swagger_ring_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	swagger "github.com/usalko/swagger-ring"
+)
+
+func TestCoverage(t *testing.T) {
+	ctx := context.Background()
+
+	source := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/orders.yaml":
+			fmt.Fprint(rw, "openapi: 3.0.2\ninfo:\n  title: Orders\n  version: 1.0.0\nservers:\n  - url: /api/v1\npaths:\n  /orders:\n    get:\n      responses: {}\n    post:\n      responses: {}\n  /orders/{id}:\n    get:\n      responses: {}\n")
+		case "/users.yaml":
+			fmt.Fprint(rw, "openapi: 3.0.2\ninfo:\n  title: Users\n  version: 1.0.0\npaths:\n  /users:\n    get:\n      responses: {}\n")
+		}
+	}))
+	defer source.Close()
+
+	cfg := swagger.CreateConfig()
+	cfg.Path = "/api/v1/docs"
+	cfg.Coverage = &swagger.CoverageConfig{}
+	cfg.Docs = append(cfg.Docs,
+		&swagger.DocPath{Path: source.URL + "/orders.yaml", Name: "orders"},
+		&swagger.DocPath{Path: source.URL + "/users.yaml", Name: "users"},
+	)
+
+	handler, err := swagger.New(ctx, http.NotFoundHandler(), cfg, "swagger-ring")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for _, request := range []struct{ method, path string }{
+		{"GET", "/api/v1/orders/1"},
+		{"GET", "/api/v1/orders/2"},
+		{"GET", "/api/v1/orders"},
+		{"DELETE", "/api/v1/orders/3"},
+		{"DELETE", "/api/v1/orders/4"},
+		{"GET", "/health"},
+	} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(request.method, request.path, nil))
+	}
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest("GET", "/api/v1/docs/coverage", nil))
+	report := &swagger.CoverageReport{}
+	if err := json.Unmarshal(rw.Body.Bytes(), report); err != nil {
+		t.Fatalf("expected JSON report, got %v", err)
+	}
+	coverage := make(map[string]*swagger.SourceCoverage)
+	for _, source := range report.Sources {
+		coverage[source.Source] = source
+	}
+
+	orders := coverage["orders"]
+	if orders == nil || orders.Operations != 3 || orders.Used != 2 {
+		t.Fatalf("expected 2 of 3 orders operations used, got %+v", orders)
+	}
+	if used := orders.UsedOperations[1]; used.Path != "/orders/{id}" || used.Count != 2 || used.LastSeen == nil {
+		t.Errorf("expected 2 requests of GET /orders/{id}, got %+v", used)
+	}
+	if unused := orders.Unused; len(unused) != 1 || unused[0].Method != "POST" || unused[0].Path != "/orders" {
+		t.Errorf("expected POST /orders unused, got %+v", unused)
+	}
+	if undocumented := orders.Undocumented; len(undocumented) != 1 || undocumented[0].Method != "DELETE" ||
+		undocumented[0].Path != "/api/v1/orders/{id}" || undocumented[0].Count != 2 {
+		t.Errorf("expected DELETE /api/v1/orders/{id} undocumented, got %+v", undocumented)
+	}
+	if users := coverage["users"]; users == nil || users.Used != 0 || len(users.Unused) != 1 {
+		t.Errorf("expected GET /users unused, got %+v", users)
+	}
+	if unknown := coverage[""]; unknown == nil || len(unknown.Undocumented) != 1 || unknown.Undocumented[0].Path != "/health" {
+		t.Errorf("expected /health undocumented without a source, got %+v", unknown)
+	}
+}
@@ -0,0 +1,80 @@
+package swagger_ring
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// DeprecationConfig adds the Deprecation (RFC 9745), Sunset (RFC 8594) and Link headers to the responses
+// of the operations that are deprecated or have an x-sunset date.
+type DeprecationConfig struct {
+	// Link is the URL of the deprecation notice, the documentation page by default.
+	// The externalDocs URL of the operation takes precedence.
+	Link string `json:"link"`
+}
+
+const (
+	sunsetExtension       = "x-sunset"
+	deprecatedAtExtension = "x-deprecated-at"
+)
+
+// deprecationHeaders sets the deprecation headers of the operation on the response.
+type deprecationHeaders struct {
+	link string
+}
+
+func newDeprecationHeaders(config *DeprecationConfig, path string) *deprecationHeaders {
+	if config == nil {
+		return nil
+	}
+	headers := &deprecationHeaders{link: config.Link}
+	if headers.link == "" {
+		headers.link = path
+	}
+	return headers
+}
+
+// parseExtensionDate reads a date or a date-time extension, YAML may already decode it as a time.
+func parseExtensionDate(value any) (time.Time, bool) {
+	switch typed := value.(type) {
+	case time.Time:
+		return typed, true
+	case string:
+		for _, layout := range []string{time.RFC3339, time.DateOnly} {
+			if parsed, err := time.Parse(layout, typed); err == nil {
+				return parsed, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// set adds the headers when the operation is deprecated or has a sunset date.
+// The headers are set before the next handler, so the service can still override them.
+func (headers *deprecationHeaders) set(header http.Header, match *operationMatch) {
+	sunset, hasSunset := parseExtensionDate(match.operation[sunsetExtension])
+	if match.operation["deprecated"] != true && !hasSunset {
+		return
+	}
+	if deprecatedAt, ok := parseExtensionDate(match.operation[deprecatedAtExtension]); ok {
+		header.Set("Deprecation", fmt.Sprintf("@%d", deprecatedAt.Unix()))
+	} else {
+		header.Set("Deprecation", "true")
+	}
+	if hasSunset {
+		header.Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+	}
+	link := headers.link
+	if externalDocs, ok := match.operation["externalDocs"].(map[string]any); ok {
+		if url, ok := externalDocs["url"].(string); ok && url != "" {
+			link = url
+		}
+	}
+	if link != "" {
+		header.Add("Link", fmt.Sprintf(`<%v>; rel="deprecation"; type="text/html"`, link))
+		if hasSunset {
+			header.Add("Link", fmt.Sprintf(`<%v>; rel="sunset"; type="text/html"`, link))
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package swagger_ring_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	swagger "github.com/usalko/swagger-ring"
+)
+
+const deprecationSource = `
+openapi: 3.0.2
+info:
+  title: Orders
+  version: 1.0.0
+paths:
+  /orders:
+    get:
+      responses: {}
+  /orders/legacy:
+    get:
+      deprecated: true
+      responses: {}
+  /orders/export:
+    get:
+      deprecated: true
+      x-deprecated-at: 2026-01-01T00:00:00Z
+      x-sunset: 2026-12-31
+      externalDocs:
+        url: https://example.com/migrate-export
+      responses: {}
+`
+
+func TestDeprecation(t *testing.T) {
+	ctx := context.Background()
+
+	source := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(rw, deprecationSource)
+	}))
+	defer source.Close()
+
+	cfg := swagger.CreateConfig()
+	cfg.Path = "/api/v1/docs"
+	cfg.Deprecation = &swagger.DeprecationConfig{}
+	cfg.Docs = append(cfg.Docs, &swagger.DocPath{Path: source.URL + "/swagger.yaml"})
+
+	handler, err := swagger.New(ctx, http.NotFoundHandler(), cfg, "swagger-ring")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tt := []struct {
+		name        string
+		path        string
+		deprecation string
+		sunset      string
+		link        []string
+	}{
+		{name: "current", path: "/orders"},
+		{name: "deprecated", path: "/orders/legacy", deprecation: "true", link: []string{`</api/v1/docs>; rel="deprecation"; type="text/html"`}},
+		{name: "sunset", path: "/orders/export", deprecation: "@1767225600", sunset: "Thu, 31 Dec 2026 00:00:00 GMT", link: []string{
+			`<https://example.com/migrate-export>; rel="deprecation"; type="text/html"`,
+			`<https://example.com/migrate-export>; rel="sunset"; type="text/html"`,
+		}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, httptest.NewRequest("GET", tc.path, nil))
+			if deprecation := rw.Header().Get("Deprecation"); deprecation != tc.deprecation {
+				t.Errorf("expected Deprecation %q, got %q", tc.deprecation, deprecation)
+			}
+			if sunset := rw.Header().Get("Sunset"); sunset != tc.sunset {
+				t.Errorf("expected Sunset %q, got %q", tc.sunset, sunset)
+			}
+			if link := rw.Header().Values("Link"); !reflect.DeepEqual(link, tc.link) && len(link)+len(tc.link) > 0 {
+				t.Errorf("expected Link %q, got %q", tc.link, link)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package swagger_ring
+
+import "strings"
+
+// dereferencer inlines the internal `$ref`s of a document.
+type dereferencer struct {
+	root     map[string]any
+	maxDepth int
+}
+
+// dereferenceDocument returns a copy of the document with every internal `$ref` inlined.
+// A reference that points back to a schema being inlined is kept as is,
+// as well as references nested deeper than maxDepth (zero means unlimited).
+func dereferenceDocument(document map[string]any, maxDepth int) map[string]any {
+	resolver := &dereferencer{root: document, maxDepth: maxDepth}
+	return resolver.resolve(document, nil).(map[string]any)
+}
+
+func (resolver *dereferencer) resolve(value any, stack []string) any {
+	switch node := value.(type) {
+	case map[string]any:
+		if ref, ok := node["$ref"].(string); ok && strings.HasPrefix(ref, "#") {
+			if resolver.keepReference(ref, stack) {
+				return deepCopy(node)
+			}
+			target, ok := lookupPointer(resolver.root, ref)
+			if !ok {
+				return deepCopy(node)
+			}
+			resolved := resolver.resolve(target, append(stack, ref))
+			// Keep the siblings of $ref (like description) over the referenced object
+			if resolvedMap, ok := resolved.(map[string]any); ok && len(node) > 1 {
+				for key, child := range node {
+					if key != "$ref" {
+						resolvedMap[key] = resolver.resolve(child, stack)
+					}
+				}
+			}
+			return resolved
+		}
+		result := make(map[string]any, len(node))
+		for key, child := range node {
+			result[key] = resolver.resolve(child, stack)
+		}
+		return result
+	case []any:
+		result := make([]any, len(node))
+		for i, child := range node {
+			result[i] = resolver.resolve(child, stack)
+		}
+		return result
+	}
+	return value
+}
+
+func (resolver *dereferencer) keepReference(ref string, stack []string) bool {
+	if resolver.maxDepth > 0 && len(stack) >= resolver.maxDepth {
+		return true
+	}
+	for _, seen := range stack {
+		if seen == ref {
+			return true
+		}
+	}
+	return false
+}
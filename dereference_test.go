@@ -0,0 +1,96 @@
+package swagger_ring_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	swagger "github.com/usalko/swagger-ring"
+)
+
+const dereferenceSource = `{
+  "openapi": "3.0.2",
+  "info": {"title": "Tree", "version": "1.0.0"},
+  "paths": {
+    "/nodes": {
+      "get": {
+        "responses": {
+          "200": {
+            "description": "OK",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Node"}}}
+          }
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Node": {
+        "type": "object",
+        "properties": {
+          "name": {"$ref": "#/components/schemas/Name"},
+          "children": {"type": "array", "items": {"$ref": "#/components/schemas/Node"}}
+        }
+      },
+      "Name": {"type": "string"}
+    }
+  }
+}`
+
+func TestDereference(t *testing.T) {
+	ctx := context.Background()
+
+	var fetches atomic.Int32
+	source := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fetches.Add(1)
+		fmt.Fprint(rw, dereferenceSource)
+	}))
+	defer source.Close()
+
+	cfg := swagger.CreateConfig()
+	cfg.Path = "/api/v1/docs"
+	cfg.CacheTTL = "1m"
+	cfg.Docs = append(cfg.Docs, &swagger.DocPath{Path: source.URL + "/swagger.json"})
+
+	handler, err := swagger.New(ctx, http.NotFoundHandler(), cfg, "swagger-ring")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, httptest.NewRequest("GET", "/api/v1/docs/swagger.json?dereference=true", nil))
+
+		var document map[string]any
+		if err := json.Unmarshal(rw.Body.Bytes(), &document); err != nil {
+			t.Fatalf("expected JSON document, got %v", err)
+		}
+		schema := document["paths"].(map[string]any)["/nodes"].(map[string]any)["get"].(map[string]any)["responses"].(map[string]any)["200"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)["schema"].(map[string]any)
+		properties := schema["properties"].(map[string]any)
+		if name := properties["name"].(map[string]any); name["type"] != "string" {
+			t.Errorf("expected inlined name schema, got %v", name)
+		}
+		items := properties["children"].(map[string]any)["items"].(map[string]any)
+		if items["$ref"] != "#/components/schemas/Node" {
+			t.Errorf("expected recursive reference to be kept, got %v", items)
+		}
+	}
+	if fetches.Load() != 1 {
+		t.Errorf("expected the dereferenced document to be cached, got %d fetches", fetches.Load())
+	}
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest("GET", "/api/v1/docs/swagger.json", nil))
+	var document map[string]any
+	if err := json.Unmarshal(rw.Body.Bytes(), &document); err != nil {
+		t.Fatalf("expected JSON document, got %v", err)
+	}
+	schema := document["paths"].(map[string]any)["/nodes"].(map[string]any)["get"].(map[string]any)["responses"].(map[string]any)["200"].(map[string]any)["content"].(map[string]any)["application/json"].(map[string]any)["schema"].(map[string]any)
+	if schema["$ref"] != "#/components/schemas/Node" {
+		t.Errorf("expected references in the regular document, got %v", schema)
+	}
+}
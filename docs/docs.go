@@ -1,28 +1,276 @@
 package docs
 
-var IndexHtml = []byte(`
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+)
+
+// Renderers of the documentation page.
+const (
+	RendererScalar    = "scalar"
+	RendererSwaggerUI = "swagger-ui"
+	RendererRedoc     = "redoc"
+)
+
+// Page describes the documentation page.
+type Page struct {
+	// Renderer is one of scalar (default), swagger-ui or redoc.
+	Renderer string
+	// Options are passed to the renderer configuration as is.
+	Options map[string]any
+	// SpecURL is the URL of the merged OpenAPI/Swagger document.
+	SpecURL string
+	// Title is the title of the page.
+	Title string
+	// Logo is the URL of an image shown above the documentation.
+	Logo string
+	// Favicon is the URL of the page icon.
+	Favicon string
+	// CSS is a custom style sheet added to the page.
+	CSS string
+	// AssetsURL is the URL the renderer files are loaded from instead of the CDN.
+	AssetsURL string
+	// Offline removes the external resources the renderer loads by default, like fonts.
+	Offline bool
+	// Sources are the documents offered next to the merged document.
+	Sources []Source
+}
+
+// Source is a document the page can switch to.
+type Source struct {
+	Name string
+	URL  string
+}
+
+var layoutTemplate = template.Must(template.New("layout").Parse(`
 <!DOCTYPE html>
 <html lang="en">
   <head>
-    <title>API Documentation</title>
+    <title>{{.Title}}</title>
     <meta charset="utf-8" />
     <meta
       name="viewport"
       content="width=device-width, initial-scale=1" />
+    {{- if .Favicon}}
+    <link rel="icon" href="{{.Favicon}}" />
+    {{- end}}
+    {{- range .Stylesheets}}
+    <link rel="stylesheet" href="{{.}}" />
+    {{- end}}
+    {{- if .CSS}}
+    <style>{{.CSS}}</style>
+    {{- end}}
   </head>
 
   <body>
+    {{- if .Logo}}
+    <header class="swagger-ring-header"><img src="{{.Logo}}" alt="{{.Title}}" /></header>
+    {{- end}}
+    {{template "renderer" .}}
+  </body>
+</html>`))
+
+// asset is a file of a renderer published on the CDN.
+type asset struct {
+	file string
+	cdn  string
+}
+
+// renderer describes how a documentation renderer is loaded and initialized.
+type renderer struct {
+	stylesheets []asset
+	scripts     []asset
+	// pickerScripts are loaded in addition to the scripts when the page offers several documents
+	pickerScripts []asset
+	template      *template.Template
+}
+
+var renderers = map[string]*renderer{
+	RendererScalar: {
+		scripts: []asset{{file: "api-reference.js", cdn: "https://cdn.jsdelivr.net/npm/@scalar/api-reference"}},
+		template: rendererTemplate(`
     <div id="app"></div>
 
     <!-- Load the Script -->
-    <script src="https://cdn.jsdelivr.net/npm/@scalar/api-reference"></script>
+    {{- range .Scripts}}
+    <script src="{{.}}"></script>
+    {{- end}}
 
     <!-- Initialize the Scalar API Reference -->
     <script>
-      Scalar.createApiReference('#app', {
-        // The URL of the OpenAPI/Swagger document
-        url: '/api/v1/docs/swagger.yaml',
-      })
-    </script>
-  </body>
-</html>`)
+      Scalar.createApiReference('#app', {{.Config}})
+    </script>`),
+	},
+	RendererSwaggerUI: {
+		stylesheets: []asset{{file: "swagger-ui.css", cdn: "https://cdn.jsdelivr.net/npm/swagger-ui-dist/swagger-ui.css"}},
+		scripts:     []asset{{file: "swagger-ui-bundle.js", cdn: "https://cdn.jsdelivr.net/npm/swagger-ui-dist/swagger-ui-bundle.js"}},
+		// The `urls` of the configuration are only read by the top bar of the standalone layout
+		pickerScripts: []asset{{file: "swagger-ui-standalone-preset.js", cdn: "https://cdn.jsdelivr.net/npm/swagger-ui-dist/swagger-ui-standalone-preset.js"}},
+		template: rendererTemplate(`
+    <div id="swagger-ui"></div>
+
+    <!-- Load the Script -->
+    {{- range .Scripts}}
+    <script src="{{.}}"></script>
+    {{- end}}
+
+    <!-- Initialize the Swagger UI -->
+    <script>
+      {{- if .Picker}}
+      window.ui = SwaggerUIBundle(Object.assign({{.Config}}, {
+        dom_id: '#swagger-ui',
+        presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+        layout: 'StandaloneLayout'
+      }))
+      {{- else}}
+      window.ui = SwaggerUIBundle(Object.assign({{.Config}}, { dom_id: '#swagger-ui' }))
+      {{- end}}
+      {{- if .OAuth}}
+      window.ui.initOAuth({{.OAuth}})
+      {{- end}}
+    </script>`),
+	},
+	RendererRedoc: {
+		scripts: []asset{{file: "redoc.standalone.js", cdn: "https://cdn.jsdelivr.net/npm/redoc/bundles/redoc.standalone.js"}},
+		template: rendererTemplate(`
+    <div id="redoc-container"></div>
+
+    <!-- Load the Script -->
+    {{- range .Scripts}}
+    <script src="{{.}}"></script>
+    {{- end}}
+
+    <!-- Initialize the Redoc -->
+    <script>
+      Redoc.init({{.SpecURL}}, {{.Config}}, document.getElementById('redoc-container'))
+    </script>`),
+	},
+}
+
+func rendererTemplate(body string) *template.Template {
+	return template.Must(template.Must(layoutTemplate.Clone()).New("renderer").Parse(body))
+}
+
+// AssetFiles returns the names of the files the renderer loads from the assets URL,
+// including the files of the document picker when the page offers several documents.
+func AssetFiles(name string, picker bool) ([]string, error) {
+	if name == "" {
+		name = RendererScalar
+	}
+	pageRenderer, ok := renderers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown renderer %q", name)
+	}
+	files := make([]string, 0, len(pageRenderer.stylesheets)+len(pageRenderer.scripts)+len(pageRenderer.pickerScripts))
+	for _, file := range append(append([]asset{}, pageRenderer.stylesheets...), pageRenderer.scriptsFor(picker)...) {
+		files = append(files, file.file)
+	}
+	return files, nil
+}
+
+// scriptsFor returns the scripts of the page, with the picker scripts after the renderer ones.
+func (pageRenderer *renderer) scriptsFor(picker bool) []asset {
+	if !picker {
+		return pageRenderer.scripts
+	}
+	return append(append([]asset{}, pageRenderer.scripts...), pageRenderer.pickerScripts...)
+}
+
+// assetURLs returns the URLs of the assets, local when the assets URL is set.
+func assetURLs(assets []asset, assetsURL string) []string {
+	urls := make([]string, 0, len(assets))
+	for _, file := range assets {
+		if assetsURL != "" {
+			urls = append(urls, assetsURL+"/"+file.file)
+			continue
+		}
+		urls = append(urls, file.cdn)
+	}
+	return urls
+}
+
+// IndexPage renders the documentation page.
+// The options are passed to the renderer configuration as is,
+// the Swagger UI `oauth` option is passed to `initOAuth`.
+func IndexPage(page *Page) ([]byte, error) {
+	name := page.Renderer
+	if name == "" {
+		name = RendererScalar
+	}
+	pageRenderer, ok := renderers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown renderer %q", name)
+	}
+	title := page.Title
+	if title == "" {
+		title = "API Documentation"
+	}
+
+	config := make(map[string]any, len(page.Options)+1)
+	for key, value := range page.Options {
+		config[key] = value
+	}
+	var oauth any
+	if name == RendererSwaggerUI {
+		oauth = config["oauth"]
+		delete(config, "oauth")
+	}
+	switch {
+	case len(page.Sources) == 0 && name != RendererRedoc:
+		// The URL of the OpenAPI/Swagger document
+		config["url"] = page.SpecURL
+	case len(page.Sources) > 0 && name == RendererRedoc:
+		return nil, fmt.Errorf("%v does not support multiple documents", name)
+	case len(page.Sources) > 0:
+		// The merged document goes first, so it is opened by default
+		documents := []any{map[string]any{"name": title, "title": title, "url": page.SpecURL}}
+		for _, source := range page.Sources {
+			documents = append(documents, map[string]any{"name": source.Name, "title": source.Name, "url": source.URL})
+		}
+		if name == RendererSwaggerUI {
+			config["urls"] = documents
+		} else {
+			config["sources"] = documents
+		}
+	}
+	if _, ok := config["withDefaultFonts"]; page.Offline && name == RendererScalar && !ok {
+		// Scalar loads its fonts from fonts.scalar.com by default
+		config["withDefaultFonts"] = false
+	}
+	if _, ok := config["validatorUrl"]; page.Offline && name == RendererSwaggerUI && !ok {
+		// Swagger UI sends the document URL to validator.swagger.io for its badge by default
+		config["validatorUrl"] = nil
+	}
+
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("wrong %v options: %w", name, err)
+	}
+	data := map[string]any{
+		// encoding/json escapes <, > and & so the configuration is safe inside a script
+		"Config":      template.JS(configJSON),
+		"SpecURL":     page.SpecURL,
+		"Title":       title,
+		"Logo":        page.Logo,
+		"Favicon":     page.Favicon,
+		"CSS":         template.CSS(page.CSS),
+		"Stylesheets": assetURLs(pageRenderer.stylesheets, page.AssetsURL),
+		"Scripts":     assetURLs(pageRenderer.scriptsFor(len(page.Sources) > 0), page.AssetsURL),
+		"Picker":      len(page.Sources) > 0,
+	}
+	if oauth != nil {
+		oauthJSON, err := json.Marshal(oauth)
+		if err != nil {
+			return nil, fmt.Errorf("wrong %v oauth options: %w", name, err)
+		}
+		data["OAuth"] = template.JS(oauthJSON)
+	}
+
+	buf := bytes.NewBufferString("")
+	if err := pageRenderer.template.ExecuteTemplate(buf, "layout", data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
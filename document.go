@@ -0,0 +1,229 @@
+package swagger_ring
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// normalizeDocument converts the YAML decoder's map[any]any nodes into map[string]any,
+// so every document is walked the same way regardless of its source format.
+func normalizeDocument(value any) any {
+	switch node := value.(type) {
+	case map[any]any:
+		result := make(map[string]any, len(node))
+		for key, child := range node {
+			result[fmt.Sprintf("%v", key)] = normalizeDocument(child)
+		}
+		return result
+	case map[string]any:
+		for key, child := range node {
+			node[key] = normalizeDocument(child)
+		}
+		return node
+	case []any:
+		for i, child := range node {
+			node[i] = normalizeDocument(child)
+		}
+		return node
+	}
+	return value
+}
+
+// sortedKeys returns the keys of the map in a stable order.
+func sortedKeys(node map[string]any) []string {
+	keys := make([]string, 0, len(node))
+	for key := range node {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// escapePointerToken escapes a single JSON pointer reference token (RFC 6901).
+func escapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+}
+
+// unescapePointerToken reverts escapePointerToken.
+func unescapePointerToken(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
+
+// splitPointer splits a JSON pointer into unescaped reference tokens.
+func splitPointer(pointer string) []string {
+	pointer = strings.TrimPrefix(pointer, "#")
+	if pointer == "" {
+		return nil
+	}
+	tokens := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, token := range tokens {
+		tokens[i] = unescapePointerToken(token)
+	}
+	return tokens
+}
+
+// joinPointer appends a reference token to a JSON pointer.
+func joinPointer(pointer string, token any) string {
+	return pointer + "/" + escapePointerToken(fmt.Sprintf("%v", token))
+}
+
+// lookupPointer resolves a JSON pointer against the document.
+func lookupPointer(document any, pointer string) (any, bool) {
+	current := document
+	for _, token := range splitPointer(pointer) {
+		switch node := current.(type) {
+		case map[string]any:
+			child, ok := node[token]
+			if !ok {
+				return nil, false
+			}
+			current = child
+		case []any:
+			index, err := strconv.Atoi(token)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			current = node[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// resolve follows an internal `$ref` of the document.
+func resolve(document map[string]any, value any) (map[string]any, string) {
+	node, _ := value.(map[string]any)
+	ref, _ := node["$ref"].(string)
+	for depth := 0; ref != "" && depth < 32; depth++ {
+		target, ok := lookupPointer(document, ref)
+		if !ok {
+			return nil, ref
+		}
+		node, _ = target.(map[string]any)
+		next, _ := node["$ref"].(string)
+		if next == "" {
+			break
+		}
+		ref = next
+	}
+	return node, ref
+}
+
+// operationParameter is a resolved parameter and the JSON pointer of its declaration.
+type operationParameter struct {
+	pointer   string
+	parameter map[string]any
+}
+
+// operationParameters returns the effective parameters of the operation keyed by location and name.
+func operationParameters(document map[string]any, pointer string, item, operation map[string]any) map[string]*operationParameter {
+	parameters := make(map[string]*operationParameter)
+	itemPointer := pointer[:strings.LastIndex(pointer, "/")]
+	for i, list := range []any{item["parameters"], operation["parameters"]} {
+		listPointer := joinPointer(itemPointer, "parameters")
+		if i > 0 {
+			listPointer = joinPointer(pointer, "parameters")
+		}
+		values, _ := list.([]any)
+		for index, value := range values {
+			parameter, _ := resolve(document, value)
+			if parameter != nil {
+				parameters[fmt.Sprintf("%v:%v", parameter["in"], parameter["name"])] = &operationParameter{
+					pointer:   joinPointer(listPointer, index),
+					parameter: parameter,
+				}
+			}
+		}
+	}
+	return parameters
+}
+
+func sortedParameterKeys(parameters map[string]*operationParameter) []string {
+	keys := make([]string, 0, len(parameters))
+	for key := range parameters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// setPointer replaces the value addressed by the JSON pointer.
+// The parent of the addressed value must exist.
+func setPointer(document any, pointer string, value any) error {
+	tokens := splitPointer(pointer)
+	if len(tokens) == 0 {
+		return fmt.Errorf("cannot replace the document root")
+	}
+	parentPointer := ""
+	for _, token := range tokens[:len(tokens)-1] {
+		parentPointer = joinPointer(parentPointer, token)
+	}
+	parent, ok := lookupPointer(document, parentPointer)
+	if !ok {
+		return fmt.Errorf("path %v not found", parentPointer)
+	}
+	last := tokens[len(tokens)-1]
+	switch node := parent.(type) {
+	case map[string]any:
+		node[last] = value
+		return nil
+	case []any:
+		index, err := strconv.Atoi(last)
+		if err != nil || index < 0 || index >= len(node) {
+			return fmt.Errorf("index %v out of range at %v", last, parentPointer)
+		}
+		node[index] = value
+		return nil
+	}
+	return fmt.Errorf("path %v is not a container", parentPointer)
+}
+
+// removePointer deletes the value addressed by the JSON pointer.
+func removePointer(document any, pointer string) error {
+	tokens := splitPointer(pointer)
+	if len(tokens) == 0 {
+		return fmt.Errorf("cannot remove the document root")
+	}
+	parentPointer := ""
+	for _, token := range tokens[:len(tokens)-1] {
+		parentPointer = joinPointer(parentPointer, token)
+	}
+	parent, ok := lookupPointer(document, parentPointer)
+	if !ok {
+		return nil
+	}
+	last := tokens[len(tokens)-1]
+	switch node := parent.(type) {
+	case map[string]any:
+		delete(node, last)
+		return nil
+	case []any:
+		index, err := strconv.Atoi(last)
+		if err != nil || index < 0 || index >= len(node) {
+			return nil
+		}
+		shrunk := append(append([]any{}, node[:index]...), node[index+1:]...)
+		return setPointer(document, parentPointer, shrunk)
+	}
+	return fmt.Errorf("path %v is not a container", parentPointer)
+}
+
+// comparePointers orders JSON pointers token by token, comparing array indexes numerically.
+func comparePointers(left, right string) int {
+	leftTokens, rightTokens := splitPointer(left), splitPointer(right)
+	for i := 0; i < len(leftTokens) && i < len(rightTokens); i++ {
+		if leftTokens[i] == rightTokens[i] {
+			continue
+		}
+		leftIndex, leftErr := strconv.Atoi(leftTokens[i])
+		rightIndex, rightErr := strconv.Atoi(rightTokens[i])
+		if leftErr == nil && rightErr == nil {
+			return leftIndex - rightIndex
+		}
+		return strings.Compare(leftTokens[i], rightTokens[i])
+	}
+	return len(leftTokens) - len(rightTokens)
+}
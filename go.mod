@@ -2,4 +2,7 @@ module github.com/usalko/swagger-ring
 
 go 1.24.1
 
-require gopkg.in/yaml.v3 v3.0.1
+require (
+	golang.org/x/crypto v0.36.0
+	gopkg.in/yaml.v3 v3.0.1
+)
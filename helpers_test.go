@@ -0,0 +1,20 @@
+package swagger_ring_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// getJSON requests the URL from the handler and decodes the JSON response.
+func getJSON[T any](t *testing.T, handler http.Handler, url string) T {
+	t.Helper()
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest("GET", url, nil))
+	var report T
+	if err := json.Unmarshal(rw.Body.Bytes(), &report); err != nil {
+		t.Fatalf("expected JSON from %s, got %v: %s", url, err, rw.Body.String())
+	}
+	return report
+}
@@ -0,0 +1,200 @@
+package swagger_ring
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// HistoryConfig configures the version history of the merged document.
+type HistoryConfig struct {
+	// Size is the number of distinct versions kept in memory. Releases are kept in addition.
+	Size int `json:"size"`
+	// Releases are named versions that are never evicted from the history.
+	Releases []*Release `json:"releases"`
+}
+
+// Release pins a version of the merged document under a stable name, e.g. 2026-Q3.
+type Release struct {
+	Name string `json:"name"`
+	// Hash is the content hash of a version listed by the history endpoint.
+	Hash string `json:"hash"`
+	// File is a YAML or JSON document loaded as the release instead of a version seen at runtime.
+	File string `json:"file"`
+}
+
+// SpecVersion describes a version of the merged document kept in the history.
+type SpecVersion struct {
+	Hash     string    `json:"hash"`
+	Time     time.Time `json:"time"`
+	Releases []string  `json:"releases,omitempty"`
+
+	document map[string]any
+}
+
+// HistoryReport lists the versions kept in the history, newest first.
+type HistoryReport struct {
+	Versions []*SpecVersion `json:"versions"`
+	// Releases maps release names to version hashes.
+	Releases map[string]string `json:"releases"`
+}
+
+const defaultHistorySize = 10
+
+// specHistory is a bounded list of distinct versions of the merged document.
+type specHistory struct {
+	size     int
+	versions []*SpecVersion
+	// releases maps release names to the pinned hashes
+	releases map[string]string
+}
+
+// newSpecHistory creates the history and loads the releases defined by files.
+func newSpecHistory(config *HistoryConfig) (*specHistory, error) {
+	if config == nil {
+		config = &HistoryConfig{}
+	}
+	history := &specHistory{size: config.Size, releases: make(map[string]string)}
+	if history.size <= 0 {
+		history.size = defaultHistorySize
+	}
+	for _, release := range config.Releases {
+		if release.Name == "" {
+			return nil, fmt.Errorf("release name cannot be empty")
+		}
+		if release.File == "" {
+			if release.Hash == "" {
+				return nil, fmt.Errorf("release %v needs a hash or a file", release.Name)
+			}
+			history.releases[release.Name] = release.Hash
+			continue
+		}
+		data, err := os.ReadFile(release.File)
+		if err != nil {
+			return nil, fmt.Errorf("read release %v: %w", release.Name, err)
+		}
+		var document map[string]any
+		if err = yaml.Unmarshal(data, &document); err != nil {
+			return nil, fmt.Errorf("wrong release %v document format: %w", release.Name, err)
+		}
+		document = normalizeDocument(document).(map[string]any)
+		hash := documentHash(document)
+		history.releases[release.Name] = hash
+		history.add(hash, document)
+	}
+	return history, nil
+}
+
+// add records a new version and evicts the oldest versions that are not released.
+func (history *specHistory) add(hash string, document map[string]any) {
+	if history.find(hash) != nil {
+		return
+	}
+	history.versions = append(history.versions, &SpecVersion{Hash: hash, Time: time.Now().UTC(), document: document})
+	unpinned := 0
+	for _, version := range history.versions {
+		if !history.pinned(version.Hash) {
+			unpinned++
+		}
+	}
+	for i := 0; unpinned > history.size && i < len(history.versions); {
+		if history.pinned(history.versions[i].Hash) {
+			i++
+			continue
+		}
+		history.versions = append(history.versions[:i], history.versions[i+1:]...)
+		unpinned--
+	}
+}
+
+func (history *specHistory) pinned(hash string) bool {
+	for _, pinned := range history.releases {
+		if pinned == hash {
+			return true
+		}
+	}
+	return false
+}
+
+func (history *specHistory) find(hash string) *SpecVersion {
+	for _, version := range history.versions {
+		if version.Hash == hash {
+			return version
+		}
+	}
+	return nil
+}
+
+// lookup returns the version by its hash or release name.
+func (history *specHistory) lookup(id string) *SpecVersion {
+	if hash, ok := history.releases[id]; ok {
+		id = hash
+	}
+	return history.find(id)
+}
+
+func (history *specHistory) report() *HistoryReport {
+	report := &HistoryReport{Versions: make([]*SpecVersion, 0, len(history.versions)), Releases: make(map[string]string)}
+	for i := len(history.versions) - 1; i >= 0; i-- {
+		version := *history.versions[i]
+		version.Releases = nil
+		for name, hash := range history.releases {
+			if hash == version.Hash {
+				version.Releases = append(version.Releases, name)
+			}
+		}
+		sort.Strings(version.Releases)
+		report.Versions = append(report.Versions, &version)
+	}
+	for name, hash := range history.releases {
+		report.Releases[name] = hash
+	}
+	return report
+}
+
+// GetHistory returns the list of the versions kept in the history as JSON.
+func (swaggerMerger *SwaggerRing) GetHistory() (string, error) {
+	// Refresh the merged document to pick up a new version
+	if _, err := swaggerMerger.GetMergedSwaggerDoc(DOC_TYPE_JSON); err != nil {
+		return "", err
+	}
+	swaggerMerger.versions.mutex.Lock()
+	history := swaggerMerger.versions.history.report()
+	swaggerMerger.versions.mutex.Unlock()
+	report, err := json.Marshal(history)
+	if err != nil {
+		return "", err
+	}
+	return string(report), nil
+}
+
+// GetHistorySnapshot returns a version of the merged document by its hash or release name.
+func (swaggerMerger *SwaggerRing) GetHistorySnapshot(id string, docType DocType) (string, error) {
+	swaggerMerger.versions.mutex.Lock()
+	version := swaggerMerger.versions.history.lookup(id)
+	swaggerMerger.versions.mutex.Unlock()
+	if version == nil {
+		return "", nil
+	}
+	return swaggerMerger.renderDocument(deepCopy(version.document).(map[string]any), docType)
+}
+
+// serveHistorySnapshot writes the version addressed by `{path}/history/{hash or release}.yaml|.json`.
+func (swaggerMerger *SwaggerRing) serveHistorySnapshot(rw http.ResponseWriter, file string) {
+	id, docType := parseDocumentName(file)
+	snapshot, err := swaggerMerger.GetHistorySnapshot(id, docType)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if snapshot == "" {
+		http.Error(rw, fmt.Sprintf("version %v not found", id), http.StatusNotFound)
+		return
+	}
+	writeDocument(rw, snapshot, docType)
+}
@@ -0,0 +1,78 @@
+package swagger_ring_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	swagger "github.com/usalko/swagger-ring"
+)
+
+func TestHistory(t *testing.T) {
+	ctx := context.Background()
+
+	var version atomic.Int32
+	source := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(rw, "openapi: 3.0.2\ninfo:\n  title: Users\n  version: 1.%d.0\npaths: {}\n", version.Load())
+	}))
+	defer source.Close()
+
+	releaseFile := filepath.Join(t.TempDir(), "release.yaml")
+	if err := os.WriteFile(releaseFile, []byte("openapi: 3.0.2\ninfo:\n  title: Users\n  version: 0.9.0\npaths: {}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := swagger.CreateConfig()
+	cfg.Path = "/api/v1/docs"
+	cfg.Docs = append(cfg.Docs, &swagger.DocPath{Path: source.URL + "/swagger.yaml"})
+	cfg.History = &swagger.HistoryConfig{
+		Size:     2,
+		Releases: []*swagger.Release{{Name: "2026-Q3", File: releaseFile}},
+	}
+
+	handler, err := swagger.New(ctx, http.NotFoundHandler(), cfg, "swagger-ring")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	var report *swagger.HistoryReport
+	for i := 0; i < 3; i++ {
+		version.Store(int32(i))
+		report = getJSON[*swagger.HistoryReport](t, handler, "/api/v1/docs/history")
+	}
+	if len(report.Versions) != 3 {
+		t.Fatalf("expected 2 versions and the release, got %+v", report.Versions)
+	}
+	if report.Versions[2].Releases[0] != "2026-Q3" || report.Releases["2026-Q3"] != report.Versions[2].Hash {
+		t.Errorf("expected the release to be kept, got %+v", report)
+	}
+
+	tt := []struct {
+		name     string
+		path     string
+		status   int
+		contains string
+	}{
+		{name: "latest by hash", path: "/api/v1/docs/history/" + report.Versions[0].Hash + ".yaml", status: http.StatusOK, contains: "version: 1.2.0"},
+		{name: "release as json", path: "/api/v1/docs/history/2026-Q3.json", status: http.StatusOK, contains: `"version":"0.9.0"`},
+		{name: "evicted", path: "/api/v1/docs/history/unknown.yaml", status: http.StatusNotFound},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, httptest.NewRequest("GET", tc.path, nil))
+			if rw.Code != tc.status {
+				t.Errorf("expected status %d, got %d", tc.status, rw.Code)
+			}
+			if !strings.Contains(rw.Body.String(), tc.contains) {
+				t.Errorf("expected response to contain %q, got %s", tc.contains, rw.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,497 @@
+package swagger_ring
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// jsonPathMatch is a node selected by a JSONPath expression.
+type jsonPathMatch struct {
+	Pointer string
+	Value   any
+}
+
+// jsonPathSegment is one step of a compiled JSONPath expression.
+type jsonPathSegment struct {
+	recursive bool
+	wildcard  bool
+	names     []string
+	indexes   []int
+	filter    *jsonPathFilter
+}
+
+// jsonPath is a compiled JSONPath expression.
+// The supported subset is the root `$`, child names (`.name`, `['name']`),
+// wildcards, array indexes, unions, recursive descent (`..`) and filters
+// such as `[?(@.deprecated == true && @.summary)]`.
+type jsonPath struct {
+	expression string
+	segments   []jsonPathSegment
+}
+
+// jsonPathFilter is a disjunction of conjunctions of filter terms.
+type jsonPathFilter struct {
+	anyOf [][]jsonPathFilterTerm
+}
+
+// jsonPathFilterTerm compares a value relative to the current node with a literal.
+type jsonPathFilterTerm struct {
+	negate   bool
+	path     []string
+	operator string
+	literal  any
+	pattern  *regexp.Regexp
+}
+
+// compileJSONPath parses a JSONPath expression.
+func compileJSONPath(expression string) (*jsonPath, error) {
+	expression = strings.TrimSpace(expression)
+	if !strings.HasPrefix(expression, "$") {
+		return nil, fmt.Errorf("jsonpath %q must start with $", expression)
+	}
+	compiled := &jsonPath{expression: expression}
+	rest := expression[1:]
+	for len(rest) > 0 {
+		segment := jsonPathSegment{}
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			segment.recursive = true
+			rest = rest[2:]
+			if strings.HasPrefix(rest, "[") {
+				break
+			}
+			name, tail := readJSONPathName(rest)
+			if name == "" {
+				return nil, fmt.Errorf("jsonpath %q: missing name after ..", expression)
+			}
+			segment.setName(name)
+			rest = tail
+			compiled.segments = append(compiled.segments, segment)
+			continue
+		case strings.HasPrefix(rest, "."):
+			name, tail := readJSONPathName(rest[1:])
+			if name == "" {
+				return nil, fmt.Errorf("jsonpath %q: missing name after .", expression)
+			}
+			segment.setName(name)
+			rest = tail
+			compiled.segments = append(compiled.segments, segment)
+			continue
+		}
+		if !strings.HasPrefix(rest, "[") {
+			return nil, fmt.Errorf("jsonpath %q: unexpected %q", expression, rest)
+		}
+		end := findJSONPathBracketEnd(rest)
+		if end < 0 {
+			return nil, fmt.Errorf("jsonpath %q: unterminated [", expression)
+		}
+		if err := segment.parseBracket(strings.TrimSpace(rest[1:end])); err != nil {
+			return nil, fmt.Errorf("jsonpath %q: %w", expression, err)
+		}
+		rest = rest[end+1:]
+		compiled.segments = append(compiled.segments, segment)
+	}
+	return compiled, nil
+}
+
+func readJSONPathName(rest string) (string, string) {
+	end := len(rest)
+	for i, r := range rest {
+		if r == '.' || r == '[' {
+			end = i
+			break
+		}
+	}
+	return rest[:end], rest[end:]
+}
+
+func findJSONPathBracketEnd(rest string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(rest); i++ {
+		switch {
+		case quote != 0:
+			if rest[i] == '\\' && quote == '/' {
+				i++
+			} else if rest[i] == quote {
+				quote = 0
+			}
+		case opensJSONPathQuote(rest, i):
+			quote = rest[i]
+		case rest[i] == '[':
+			depth++
+		case rest[i] == ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// opensJSONPathQuote reports whether the character at i starts a string literal
+// or the regular expression literal of a `=~` filter.
+func opensJSONPathQuote(value string, i int) bool {
+	if value[i] == '\'' || value[i] == '"' {
+		return true
+	}
+	return value[i] == '/' && strings.HasSuffix(strings.TrimRight(value[:i], " "), "=~")
+}
+
+func (segment *jsonPathSegment) setName(name string) {
+	if name == "*" {
+		segment.wildcard = true
+		return
+	}
+	segment.names = append(segment.names, name)
+}
+
+func (segment *jsonPathSegment) parseBracket(content string) error {
+	if content == "*" {
+		segment.wildcard = true
+		return nil
+	}
+	if strings.HasPrefix(content, "?") {
+		filter, err := parseJSONPathFilter(strings.TrimSpace(content[1:]))
+		if err != nil {
+			return err
+		}
+		segment.filter = filter
+		return nil
+	}
+	for _, part := range splitOutsideQuotes(content, ",") {
+		part = strings.TrimSpace(part)
+		if unquoted, ok := unquoteJSONPathString(part); ok {
+			segment.names = append(segment.names, unquoted)
+			continue
+		}
+		index, err := strconv.Atoi(part)
+		if err != nil {
+			return fmt.Errorf("invalid selector %q", part)
+		}
+		segment.indexes = append(segment.indexes, index)
+	}
+	return nil
+}
+
+func unquoteJSONPathString(value string) (string, bool) {
+	if len(value) >= 2 && (value[0] == '\'' || value[0] == '"') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1], true
+	}
+	return "", false
+}
+
+// splitOutsideQuotes splits the string by the separator ignoring quoted parts and regular expressions.
+func splitOutsideQuotes(value, separator string) []string {
+	parts := make([]string, 0, 2)
+	var quote byte
+	last := 0
+	for i := 0; i < len(value); i++ {
+		switch {
+		case quote != 0:
+			if value[i] == '\\' && quote == '/' {
+				i++
+			} else if value[i] == quote {
+				quote = 0
+			}
+		case opensJSONPathQuote(value, i):
+			quote = value[i]
+		case strings.HasPrefix(value[i:], separator):
+			parts = append(parts, value[last:i])
+			last = i + len(separator)
+			i += len(separator) - 1
+		}
+	}
+	return append(parts, value[last:])
+}
+
+func parseJSONPathFilter(content string) (*jsonPathFilter, error) {
+	if strings.HasPrefix(content, "(") && strings.HasSuffix(content, ")") {
+		content = content[1 : len(content)-1]
+	}
+	filter := &jsonPathFilter{}
+	for _, alternative := range splitOutsideQuotes(content, "||") {
+		terms := make([]jsonPathFilterTerm, 0, 1)
+		for _, expression := range splitOutsideQuotes(alternative, "&&") {
+			term, err := parseJSONPathFilterTerm(strings.TrimSpace(expression))
+			if err != nil {
+				return nil, err
+			}
+			terms = append(terms, term)
+		}
+		filter.anyOf = append(filter.anyOf, terms)
+	}
+	return filter, nil
+}
+
+var jsonPathOperators = []string{"==", "!=", "<=", ">=", "=~", "<", ">"}
+
+func parseJSONPathFilterTerm(expression string) (jsonPathFilterTerm, error) {
+	term := jsonPathFilterTerm{}
+	if strings.HasPrefix(expression, "!") {
+		term.negate = true
+		expression = strings.TrimSpace(expression[1:])
+	}
+	left := expression
+	for _, operator := range jsonPathOperators {
+		parts := splitOutsideQuotes(expression, operator)
+		if len(parts) == 1 {
+			continue
+		}
+		if len(parts) > 2 {
+			return term, fmt.Errorf("invalid filter expression %q", expression)
+		}
+		term.operator = operator
+		left = strings.TrimSpace(parts[0])
+		literal := strings.TrimSpace(parts[1])
+		if operator == "=~" {
+			pattern, ok := unquoteJSONPathString(literal)
+			if !ok && len(literal) >= 2 && literal[0] == '/' && literal[len(literal)-1] == '/' {
+				pattern, ok = literal[1:len(literal)-1], true
+			}
+			if !ok {
+				return term, fmt.Errorf("invalid filter regexp %q", literal)
+			}
+			compiled, err := regexp.Compile(pattern)
+			if err != nil {
+				return term, fmt.Errorf("invalid filter regexp %q: %w", literal, err)
+			}
+			term.pattern = compiled
+			break
+		}
+		value, err := parseJSONPathLiteral(literal)
+		if err != nil {
+			return term, err
+		}
+		term.literal = value
+		break
+	}
+	if left != "@" && !strings.HasPrefix(left, "@.") && !strings.HasPrefix(left, "@[") {
+		return term, fmt.Errorf("invalid filter expression %q", expression)
+	}
+	relative, err := compileJSONPath("$" + left[1:])
+	if err != nil {
+		return term, err
+	}
+	for _, segment := range relative.segments {
+		if len(segment.names) != 1 || segment.recursive || segment.wildcard {
+			return term, fmt.Errorf("unsupported filter path %q", left)
+		}
+		term.path = append(term.path, segment.names[0])
+	}
+	// Leftovers of a malformed expression end up in the unquoted names
+	if strings.ContainsAny(stripJSONPathQuotes(left), " =!<>~&|()/") {
+		return term, fmt.Errorf("invalid filter expression %q", expression)
+	}
+	return term, nil
+}
+
+// stripJSONPathQuotes removes the quoted names from the expression.
+func stripJSONPathQuotes(value string) string {
+	var result strings.Builder
+	var quote byte
+	for i := 0; i < len(value); i++ {
+		switch {
+		case quote != 0:
+			if value[i] == quote {
+				quote = 0
+			}
+		case value[i] == '\'' || value[i] == '"':
+			quote = value[i]
+		default:
+			result.WriteByte(value[i])
+		}
+	}
+	return result.String()
+}
+
+func parseJSONPathLiteral(literal string) (any, error) {
+	if unquoted, ok := unquoteJSONPathString(literal); ok {
+		return unquoted, nil
+	}
+	switch literal {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	if number, err := strconv.ParseFloat(literal, 64); err == nil {
+		return number, nil
+	}
+	return nil, fmt.Errorf("invalid filter literal %q", literal)
+}
+
+func (filter *jsonPathFilter) matches(value any) bool {
+	for _, terms := range filter.anyOf {
+		matched := true
+		for _, term := range terms {
+			if !term.matches(value) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (term jsonPathFilterTerm) matches(value any) bool {
+	current, exists := value, true
+	for _, name := range term.path {
+		node, ok := current.(map[string]any)
+		if !ok {
+			exists = false
+			break
+		}
+		if current, ok = node[name]; !ok {
+			exists = false
+			break
+		}
+	}
+	result := false
+	switch term.operator {
+	case "":
+		result = exists && current != nil && current != false
+	case "==":
+		result = exists && literalEqual(current, term.literal)
+	case "!=":
+		result = !exists || !literalEqual(current, term.literal)
+	case "=~":
+		text, ok := current.(string)
+		result = exists && ok && term.pattern.MatchString(text)
+	default:
+		left, leftOk := toFloat(current)
+		right, rightOk := toFloat(term.literal)
+		if exists && leftOk && rightOk {
+			switch term.operator {
+			case "<":
+				result = left < right
+			case "<=":
+				result = left <= right
+			case ">":
+				result = left > right
+			case ">=":
+				result = left >= right
+			}
+		}
+	}
+	if term.negate {
+		return !result
+	}
+	return result
+}
+
+func literalEqual(value, literal any) bool {
+	if left, ok := toFloat(value); ok {
+		if right, ok := toFloat(literal); ok {
+			return left == right
+		}
+	}
+	return reflect.DeepEqual(value, literal)
+}
+
+// toFloat converts any numeric value decoded from YAML or JSON to float64.
+func toFloat(value any) (float64, bool) {
+	switch number := value.(type) {
+	case int:
+		return float64(number), true
+	case int64:
+		return float64(number), true
+	case uint64:
+		return float64(number), true
+	case float32:
+		return float64(number), true
+	case float64:
+		return number, true
+	}
+	return 0, false
+}
+
+// Select returns every node of the document matched by the expression.
+func (path *jsonPath) Select(document any) []jsonPathMatch {
+	current := []jsonPathMatch{{Pointer: "", Value: document}}
+	for _, segment := range path.segments {
+		next := make([]jsonPathMatch, 0, len(current))
+		for _, match := range current {
+			if segment.recursive {
+				for _, descendant := range descendants(match) {
+					next = append(next, segment.apply(descendant)...)
+				}
+				continue
+			}
+			next = append(next, segment.apply(match)...)
+		}
+		current = next
+	}
+	return current
+}
+
+// descendants returns the node and all nodes below it in document order.
+func descendants(match jsonPathMatch) []jsonPathMatch {
+	result := []jsonPathMatch{match}
+	for _, child := range children(match) {
+		result = append(result, descendants(child)...)
+	}
+	return result
+}
+
+// children returns the direct children of an object or array node.
+func children(match jsonPathMatch) []jsonPathMatch {
+	switch node := match.Value.(type) {
+	case map[string]any:
+		result := make([]jsonPathMatch, 0, len(node))
+		for _, key := range sortedKeys(node) {
+			result = append(result, jsonPathMatch{Pointer: joinPointer(match.Pointer, key), Value: node[key]})
+		}
+		return result
+	case []any:
+		result := make([]jsonPathMatch, 0, len(node))
+		for i, child := range node {
+			result = append(result, jsonPathMatch{Pointer: joinPointer(match.Pointer, i), Value: child})
+		}
+		return result
+	}
+	return nil
+}
+
+func (segment jsonPathSegment) apply(match jsonPathMatch) []jsonPathMatch {
+	if segment.wildcard {
+		return children(match)
+	}
+	if segment.filter != nil {
+		result := make([]jsonPathMatch, 0)
+		for _, child := range children(match) {
+			if segment.filter.matches(child.Value) {
+				result = append(result, child)
+			}
+		}
+		return result
+	}
+	result := make([]jsonPathMatch, 0, 1)
+	switch node := match.Value.(type) {
+	case map[string]any:
+		for _, name := range segment.names {
+			if child, ok := node[name]; ok {
+				result = append(result, jsonPathMatch{Pointer: joinPointer(match.Pointer, name), Value: child})
+			}
+		}
+	case []any:
+		for _, index := range segment.indexes {
+			if index < 0 {
+				index += len(node)
+			}
+			if index >= 0 && index < len(node) {
+				result = append(result, jsonPathMatch{Pointer: joinPointer(match.Pointer, index), Value: node[index]})
+			}
+		}
+	}
+	return result
+}
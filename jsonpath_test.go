@@ -0,0 +1,96 @@
+package swagger_ring
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestJSONPath(t *testing.T) {
+	document := map[string]any{
+		"info": map[string]any{"title": "Users", "x-version": "1.0"},
+		"paths": map[string]any{
+			"/users": map[string]any{
+				"get":    map[string]any{"summary": "List users", "x-rate": 10},
+				"delete": map[string]any{"summary": "Drop users", "deprecated": true, "x-rate": 1},
+			},
+			"/users/{id}": map[string]any{
+				"get": map[string]any{"summary": "Get a==b user", "x-rate": 100, "tags": []any{"users", "admin"}},
+			},
+		},
+		"tags": []any{
+			map[string]any{"name": "users"},
+			map[string]any{"name": "admin"},
+			map[string]any{"name": "orders"},
+		},
+	}
+
+	tt := []struct {
+		name       string
+		expression string
+		pointers   []string
+	}{
+		{name: "root", expression: "$", pointers: []string{""}},
+		{name: "child", expression: "$.info.title", pointers: []string{"/info/title"}},
+		{name: "quoted child", expression: "$.paths['/users'].get", pointers: []string{"/paths/~1users/get"}},
+		{name: "union", expression: "$.info['title','x-version']", pointers: []string{"/info/title", "/info/x-version"}},
+		{name: "wildcard", expression: "$.paths['/users'].*", pointers: []string{"/paths/~1users/delete", "/paths/~1users/get"}},
+		{name: "index", expression: "$.tags[1].name", pointers: []string{"/tags/1/name"}},
+		{name: "negative index", expression: "$.tags[-1]", pointers: []string{"/tags/2"}},
+		{name: "index union", expression: "$.tags[0,2]", pointers: []string{"/tags/0", "/tags/2"}},
+		{name: "out of range index", expression: "$.tags[5]", pointers: []string{}},
+		{name: "recursive descent", expression: "$..summary", pointers: []string{"/paths/~1users/delete/summary", "/paths/~1users/get/summary", "/paths/~1users~1{id}/get/summary"}},
+		{name: "recursive descent with brackets", expression: "$..tags[0]", pointers: []string{"/tags/0", "/paths/~1users~1{id}/get/tags/0"}},
+		{name: "existence filter", expression: "$.paths.*[?(@.deprecated)]", pointers: []string{"/paths/~1users/delete"}},
+		{name: "negated filter", expression: "$.paths['/users'][?(!@.deprecated)]", pointers: []string{"/paths/~1users/get"}},
+		{name: "equality filter", expression: "$.tags[?(@.name == 'admin')]", pointers: []string{"/tags/1"}},
+		{name: "inequality filter", expression: "$.tags[?(@.name != 'admin')]", pointers: []string{"/tags/0", "/tags/2"}},
+		{name: "numeric filter", expression: "$.paths.*[?(@.x-rate >= 10)]", pointers: []string{"/paths/~1users/get", "/paths/~1users~1{id}/get"}},
+		{name: "less than filter", expression: "$.paths.*[?(@.x-rate < 10)]", pointers: []string{"/paths/~1users/delete"}},
+		{name: "and filter", expression: "$.paths.*[?(@.x-rate > 5 && @.x-rate < 50)]", pointers: []string{"/paths/~1users/get"}},
+		{name: "or filter", expression: "$.tags[?(@.name == 'users' || @.name == 'orders')]", pointers: []string{"/tags/0", "/tags/2"}},
+		{name: "regexp filter", expression: "$.tags[?(@.name =~ /^ad/)]", pointers: []string{"/tags/1"}},
+		{name: "regexp filter with operators", expression: "$.paths.*[?(@.summary =~ /a==b/)]", pointers: []string{"/paths/~1users~1{id}/get"}},
+		{name: "regexp filter with a bracket", expression: "$.tags[?(@.name =~ /[o]rders/)]", pointers: []string{"/tags/2"}},
+		{name: "quoted regexp filter", expression: "$.tags[?(@.name =~ 'min$')]", pointers: []string{"/tags/1"}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			path, err := compileJSONPath(tc.expression)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			pointers := make([]string, 0)
+			for _, match := range path.Select(document) {
+				pointers = append(pointers, match.Pointer)
+			}
+			if !reflect.DeepEqual(pointers, tc.pointers) {
+				t.Errorf("expected %v, got %v", tc.pointers, pointers)
+			}
+		})
+	}
+}
+
+func TestJSONPathErrors(t *testing.T) {
+	for _, expression := range []string{
+		"",
+		"paths",
+		"$.",
+		"$..",
+		"$.paths[",
+		"$.tags[one]",
+		"$.tags[?(@.name == admin)]",
+		"$.tags[?(@.name == 'a' == 'b')]",
+		"$.tags[?(@.name ~ 'a')]",
+		"$.tags[?(@.name =~ admin)]",
+		"$.tags[?(@.name =~ /[/)]",
+		"$.tags[?(name == 'admin')]",
+		"$.tags[?(@..name)]",
+		"$.tags[?(@.* == 'admin')]",
+	} {
+		t.Run(expression, func(t *testing.T) {
+			if _, err := compileJSONPath(expression); err == nil {
+				t.Errorf("expected an error for %q", expression)
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package swagger_ring
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// LintConfig configures the guideline rules checked over the merged document.
+type LintConfig struct {
+	// Disable lists the names of the built-in rules to skip.
+	Disable []string `json:"disable"`
+	// Rules are additional rules. A rule named after a built-in one replaces it.
+	Rules []*LintRule `json:"rules"`
+}
+
+// LintRule checks a field of every node selected by a JSONPath expression.
+type LintRule struct {
+	// Name identifies the rule in the report.
+	Name string `json:"name"`
+	// Description is the message reported for every violation.
+	Description string `json:"description"`
+	// Severity is one of error, warning or info. The default is warning.
+	Severity string `json:"severity"`
+	// Given is a JSONPath expression selecting the nodes to check.
+	Given string `json:"given"`
+	// KeyPattern limits the check to the selected nodes whose key matches the regular expression.
+	KeyPattern string `json:"keyPattern"`
+	// Field is a dotted path of the checked value relative to the selected node,
+	// `@key` checks the key of the node and an empty field checks the node itself.
+	Field string `json:"field"`
+	// Condition is one of truthy, falsy, defined, undefined, pattern, notPattern, enum or equals.
+	Condition string `json:"condition"`
+	// Pattern is the regular expression of the pattern and notPattern conditions.
+	Pattern string `json:"pattern"`
+	// Values are the allowed values of the enum condition, the first one is expected by equals.
+	Values []string `json:"values"`
+
+	given      *jsonPath
+	keyPattern *regexp.Regexp
+	pattern    *regexp.Regexp
+}
+
+// LintResult is a single rule violation.
+type LintResult struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Pointer  string `json:"pointer"`
+	Message  string `json:"message"`
+	Source   string `json:"source,omitempty"`
+}
+
+// LintReport is the result of linting the merged document.
+type LintReport struct {
+	// Summary counts the results by severity.
+	Summary map[string]int `json:"summary"`
+	// Sources counts the results by source and severity.
+	Sources map[string]map[string]int `json:"sources"`
+	Results []*LintResult             `json:"results"`
+}
+
+const operationsJSONPath = "$.paths.*['get','put','post','delete','options','head','patch','trace']"
+
+// builtinLintRules are the guidelines checked unless disabled.
+var builtinLintRules = []LintRule{
+	{
+		Name:        "operation-summary",
+		Description: "Operation must have a summary.",
+		Given:       operationsJSONPath,
+		Field:       "summary",
+		Condition:   "truthy",
+	},
+	{
+		Name:        "operation-operationId",
+		Description: "Operation must have an operationId.",
+		Given:       operationsJSONPath,
+		Field:       "operationId",
+		Condition:   "truthy",
+	},
+	{
+		Name:        "path-kebab-case",
+		Description: "Path segments must be kebab-case.",
+		Given:       "$.paths.*",
+		Field:       "@key",
+		Condition:   "pattern",
+		Pattern:     `^(/([a-z0-9]+(-[a-z0-9]+)*|{[^}]+}))*/?$`,
+	},
+	{
+		Name:        "tag-description",
+		Description: "Tag must have a description.",
+		Given:       "$.tags[*]",
+		Field:       "description",
+		Condition:   "truthy",
+		Severity:    "info",
+	},
+}
+
+var lintConditions = map[string]bool{
+	"truthy": true, "falsy": true, "defined": true, "undefined": true,
+	"pattern": true, "notPattern": true, "enum": true, "equals": true,
+}
+
+var lintSeverities = map[string]bool{"error": true, "warning": true, "info": true}
+
+// compileLintRules combines the built-in and configured rules and compiles them.
+func compileLintRules(config *LintConfig) ([]*LintRule, error) {
+	if config == nil {
+		config = &LintConfig{}
+	}
+	disabled := make(map[string]bool, len(config.Disable))
+	for _, name := range config.Disable {
+		disabled[name] = true
+	}
+	for _, rule := range config.Rules {
+		disabled[rule.Name] = true
+	}
+	rules := make([]*LintRule, 0, len(builtinLintRules)+len(config.Rules))
+	for i := range builtinLintRules {
+		if !disabled[builtinLintRules[i].Name] {
+			rule := builtinLintRules[i]
+			rules = append(rules, &rule)
+		}
+	}
+	for _, rule := range config.Rules {
+		rule := *rule
+		rules = append(rules, &rule)
+	}
+	for _, rule := range rules {
+		if err := rule.compile(); err != nil {
+			return nil, fmt.Errorf("invalid lint rule %v: %w", rule.Name, err)
+		}
+	}
+	return rules, nil
+}
+
+func (rule *LintRule) compile() error {
+	if rule.Name == "" {
+		return fmt.Errorf("name cannot be empty")
+	}
+	if rule.Severity == "" {
+		rule.Severity = "warning"
+	}
+	if !lintSeverities[rule.Severity] {
+		return fmt.Errorf("unknown severity %q", rule.Severity)
+	}
+	if !lintConditions[rule.Condition] {
+		return fmt.Errorf("unknown condition %q", rule.Condition)
+	}
+	given, err := compileJSONPath(rule.Given)
+	if err != nil {
+		return err
+	}
+	rule.given = given
+	if rule.KeyPattern != "" {
+		if rule.keyPattern, err = regexp.Compile(rule.KeyPattern); err != nil {
+			return fmt.Errorf("invalid keyPattern: %w", err)
+		}
+	}
+	if rule.Condition == "pattern" || rule.Condition == "notPattern" {
+		if rule.pattern, err = regexp.Compile(rule.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+	}
+	if (rule.Condition == "enum" || rule.Condition == "equals") && len(rule.Values) == 0 {
+		return fmt.Errorf("condition %v requires values", rule.Condition)
+	}
+	if rule.Description == "" {
+		rule.Description = fmt.Sprintf("%v must be %v", rule.Field, rule.Condition)
+	}
+	return nil
+}
+
+// lintDocument runs the rules over the merged document.
+func lintDocument(merged *mergedDocument, rules []*LintRule) *LintReport {
+	report := &LintReport{
+		Summary: map[string]int{"error": 0, "warning": 0, "info": 0},
+		Sources: make(map[string]map[string]int),
+		Results: make([]*LintResult, 0),
+	}
+	for _, rule := range rules {
+		for _, match := range rule.given.Select(merged.document) {
+			key := ""
+			if tokens := splitPointer(match.Pointer); len(tokens) > 0 {
+				key = tokens[len(tokens)-1]
+			}
+			if rule.keyPattern != nil && !rule.keyPattern.MatchString(key) {
+				continue
+			}
+			pointer, value, exists := match.Pointer, any(key), true
+			if rule.Field != "@key" {
+				pointer, value, exists = lintField(match, rule.Field)
+			}
+			if rule.check(value, exists) {
+				continue
+			}
+			result := &LintResult{
+				Rule:     rule.Name,
+				Severity: rule.Severity,
+				Pointer:  pointer,
+				Message:  rule.Description,
+				Source:   merged.sourceOf(pointer),
+			}
+			report.Results = append(report.Results, result)
+			report.Summary[result.Severity]++
+			if report.Sources[result.Source] == nil {
+				report.Sources[result.Source] = make(map[string]int)
+			}
+			report.Sources[result.Source][result.Severity]++
+		}
+	}
+	sort.SliceStable(report.Results, func(i, j int) bool {
+		return comparePointers(report.Results[i].Pointer, report.Results[j].Pointer) < 0
+	})
+	return report
+}
+
+// lintField resolves the dotted field path relative to the matched node.
+func lintField(match jsonPathMatch, field string) (string, any, bool) {
+	pointer, value := match.Pointer, match.Value
+	if field == "" {
+		return pointer, value, true
+	}
+	for _, name := range strings.Split(field, ".") {
+		node, ok := value.(map[string]any)
+		if !ok {
+			return pointer, nil, false
+		}
+		if value, ok = node[name]; !ok {
+			return pointer, nil, false
+		}
+		pointer = joinPointer(pointer, name)
+	}
+	return pointer, value, true
+}
+
+// check reports whether the value satisfies the rule condition.
+func (rule *LintRule) check(value any, exists bool) bool {
+	truthy := exists && value != nil && value != false && value != "" && value != 0
+	switch rule.Condition {
+	case "truthy":
+		return truthy
+	case "falsy":
+		return !truthy
+	case "defined":
+		return exists
+	case "undefined":
+		return !exists
+	}
+	if !exists {
+		return true
+	}
+	text := fmt.Sprintf("%v", value)
+	switch rule.Condition {
+	case "pattern":
+		return rule.pattern.MatchString(text)
+	case "notPattern":
+		return !rule.pattern.MatchString(text)
+	case "enum":
+		for _, allowed := range rule.Values {
+			if text == allowed {
+				return true
+			}
+		}
+		return false
+	case "equals":
+		return text == rule.Values[0]
+	}
+	return true
+}
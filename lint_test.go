@@ -0,0 +1,101 @@
+package swagger_ring_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	swagger "github.com/usalko/swagger-ring"
+)
+
+const lintSource = `
+openapi: 3.0.2
+info:
+  title: Profiles
+  version: 1.0.0
+paths:
+  /user-profiles:
+    get:
+      summary: List profiles
+      operationId: listProfiles
+      responses:
+        200:
+          description: OK
+  /UserProfiles/{id}:
+    get:
+      operationId: getProfile
+      responses:
+        404:
+          description: Not found
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/NotFound'
+`
+
+func TestLint(t *testing.T) {
+	ctx := context.Background()
+
+	source := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(rw, lintSource)
+	}))
+	defer source.Close()
+
+	cfg := swagger.CreateConfig()
+	cfg.Path = "/api/v1/docs"
+	cfg.Docs = append(cfg.Docs, &swagger.DocPath{Path: source.URL + "/swagger.yaml"})
+	cfg.Lint = &swagger.LintConfig{
+		Disable: []string{"tag-description"},
+		Rules: []*swagger.LintRule{{
+			Name:        "error-response-schema",
+			Description: "Error responses must use the common Error schema.",
+			Severity:    "error",
+			Given:       "$.paths.*.*.responses.*",
+			KeyPattern:  "^[45]",
+			Field:       "content.application/json.schema.$ref",
+			Condition:   "equals",
+			Values:      []string{"#/components/schemas/Error"},
+		}},
+	}
+
+	handler, err := swagger.New(ctx, http.NotFoundHandler(), cfg, "swagger-ring")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest("GET", "/api/v1/docs/lint", nil))
+	report := &swagger.LintReport{}
+	if err := json.Unmarshal(rw.Body.Bytes(), report); err != nil {
+		t.Fatalf("expected JSON report, got %v", err)
+	}
+
+	rules := make(map[string]string)
+	for _, result := range report.Results {
+		rules[result.Rule] = result.Pointer
+	}
+	expected := map[string]string{
+		"operation-summary":     "/paths/~1UserProfiles~1{id}/get",
+		"path-kebab-case":       "/paths/~1UserProfiles~1{id}",
+		"error-response-schema": "/paths/~1UserProfiles~1{id}/get/responses/404/content/application~1json/schema/$ref",
+	}
+	for rule, pointer := range expected {
+		if rules[rule] != pointer {
+			t.Errorf("expected %v at %v, got %v", rule, pointer, rules[rule])
+		}
+	}
+	if len(report.Results) != len(expected) {
+		t.Errorf("expected %d results, got %+v", len(expected), report.Results)
+	}
+	if counts := report.Sources["127.0.0.1"]; counts["error"] != 1 || counts["warning"] != 2 {
+		t.Errorf("expected 1 error and 2 warnings for the source, got %v", counts)
+	}
+
+	cfg.Lint.Rules[0].Condition = "unknown"
+	if _, err := swagger.New(ctx, http.NotFoundHandler(), cfg, "swagger-ring"); err == nil {
+		t.Error("expected error for unknown lint condition, got nil")
+	}
+}
@@ -0,0 +1,128 @@
+package swagger_ring
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"strings"
+)
+
+// LogConfig configures the plugin logs.
+type LogConfig struct {
+	// Level is the minimal level of the logged messages: debug, info (default), warn or error.
+	Level string `json:"level"`
+	// Format is text (default) or json.
+	Format string `json:"format"`
+}
+
+const redacted = "[REDACTED]"
+
+// secretKeys are the configuration keys and log attributes whose values are never logged.
+var secretKeys = map[string]bool{
+	"password":      true,
+	"apikey":        true,
+	"apikeys":       true,
+	"token":         true,
+	"accesstoken":   true,
+	"secret":        true,
+	"clientsecret":  true,
+	"authorization": true,
+}
+
+// newLogger creates the logger of the middleware, every message carries its name.
+// Traefik collects the plugin output from stderr.
+func newLogger(config *LogConfig, name string, output io.Writer) (*slog.Logger, error) {
+	if config == nil {
+		config = &LogConfig{}
+	}
+	level := slog.LevelInfo
+	if config.Level != "" {
+		if err := level.UnmarshalText([]byte(config.Level)); err != nil {
+			return nil, fmt.Errorf("invalid log level %q", config.Level)
+		}
+	}
+	options := &slog.HandlerOptions{Level: level, ReplaceAttr: redactAttr}
+	var handler slog.Handler
+	switch config.Format {
+	case "", "text":
+		handler = slog.NewTextHandler(output, options)
+	case "json":
+		handler = slog.NewJSONHandler(output, options)
+	default:
+		return nil, fmt.Errorf("invalid log format %q", config.Format)
+	}
+	return slog.New(handler).With("name", name), nil
+}
+
+// redactAttr hides the secret attributes and the credentials of the logged URLs.
+func redactAttr(_ []string, attr slog.Attr) slog.Attr {
+	if isSecretKey(attr.Key) {
+		return slog.String(attr.Key, redacted)
+	}
+	if attr.Value.Kind() == slog.KindString {
+		return slog.String(attr.Key, redactURL(attr.Value.String()))
+	}
+	return attr
+}
+
+// isSecretKey tells whether the key names a secret, ignoring case, `_` and `-` (api_key, Access-Token).
+func isSecretKey(key string) bool {
+	return secretKeys[strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))]
+}
+
+// redactURL hides the password of a URL and the query values named like secrets. Other values are returned as is.
+func redactURL(value string) string {
+	if !strings.Contains(value, "://") {
+		return value
+	}
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return value
+	}
+	if parsed.RawQuery != "" {
+		parameters := strings.Split(parsed.RawQuery, "&")
+		for i, parameter := range parameters {
+			key, _, _ := strings.Cut(parameter, "=")
+			if name, err := url.QueryUnescape(key); err == nil && isSecretKey(name) {
+				parameters[i] = key + "=" + redacted
+			}
+		}
+		parsed.RawQuery = strings.Join(parameters, "&")
+	}
+	return parsed.Redacted()
+}
+
+// redactConfig returns the configuration as a generic document without secrets.
+func redactConfig(config *Config) any {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil
+	}
+	var document any
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil
+	}
+	return redactValue(document)
+}
+
+func redactValue(value any) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		for key, child := range typed {
+			if isSecretKey(key) {
+				typed[key] = redacted
+				continue
+			}
+			typed[key] = redactValue(child)
+		}
+	case []any:
+		for i, child := range typed {
+			typed[i] = redactValue(child)
+		}
+	case string:
+		return redactURL(typed)
+	}
+	return value
+}
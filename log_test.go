@@ -0,0 +1,87 @@
+package swagger_ring_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	swagger "github.com/usalko/swagger-ring"
+)
+
+func TestLog(t *testing.T) {
+	ctx := context.Background()
+
+	source := httptest.NewServer(http.NotFoundHandler())
+	defer source.Close()
+
+	output, err := os.CreateTemp(t.TempDir(), "log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr := os.Stderr
+	os.Stderr = output
+	defer func() { os.Stderr = stderr }()
+
+	cfg := swagger.CreateConfig()
+	cfg.Path = "/api/v1/docs"
+	cfg.Log = &swagger.LogConfig{Level: "debug", Format: "json"}
+	cfg.Auth = &swagger.AuthConfig{APIKeys: []string{"key-secret"}}
+	cfg.Docs = append(cfg.Docs, &swagger.DocPath{Path: strings.Replace(source.URL, "http://", "http://docs:pass-secret@", 1) + "/swagger.txt", Name: "orders"})
+	cfg.Docs = append(cfg.Docs, &swagger.DocPath{Path: source.URL + "/users.txt?version=2&token=tok-secret&api_key=api-secret&Access-Token=access-secret", Name: "users"})
+	cfg.Docs = append(cfg.Docs, &swagger.DocPath{Path: "http://127.0.0.1:0/unreachable.txt?access_token=dial-secret", Name: "unreachable"})
+
+	handler, err := swagger.New(ctx, http.NotFoundHandler(), cfg, "swagger-ring")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	req := httptest.NewRequest("GET", "/api/v1/docs/swagger.yaml", nil)
+	req.Header.Set("X-API-Key", "key-secret")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	status := httptest.NewRecorder()
+	req = httptest.NewRequest("GET", "/api/v1/docs/_status", nil)
+	req.Header.Set("X-API-Key", "key-secret")
+	handler.ServeHTTP(status, req)
+	os.Stderr = stderr
+
+	if body := status.Body.String(); strings.Contains(body, "secret") || !strings.Contains(body, "version=2") {
+		t.Errorf("expected secret query parameters to be redacted on the status page, got %s", body)
+	}
+
+	data, err := os.ReadFile(output.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "secret") {
+		t.Errorf("expected secrets to be redacted, got %s", data)
+	}
+	messages := make(map[string]map[string]any)
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for scanner.Scan() {
+		var record map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("expected JSON log record, got %v", scanner.Text())
+		}
+		if record["name"] != "swagger-ring" || record["source"] == nil {
+			t.Errorf("expected name and source in %v", scanner.Text())
+		}
+		messages[record["msg"].(string)+" "+record["source"].(string)] = record
+	}
+	for _, name := range []string{"orders", "users", "unreachable"} {
+		if record := messages["source skipped "+name]; record == nil || record["level"] != "WARN" {
+			t.Errorf("expected a warning for the %s source, got %v", name, record)
+		}
+	}
+	if messages["configuration loaded config"] == nil {
+		t.Error("expected the configuration at the debug level")
+	}
+
+	cfg.Log.Level = "verbose"
+	if _, err := swagger.New(ctx, http.NotFoundHandler(), cfg, "swagger-ring"); err == nil {
+		t.Error("expected error for an unknown log level, got nil")
+	}
+}
@@ -0,0 +1,182 @@
+package swagger_ring
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultBuckets are the upper bounds of the duration histograms in seconds.
+var defaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// metricFamily is a counter or a histogram with its series by label values.
+type metricFamily struct {
+	name    string
+	help    string
+	labels  []string
+	buckets []float64
+	series  map[string]*metricSeries
+}
+
+// metricSeries is the value of a counter, or the sum, count and bucket counts of a histogram.
+type metricSeries struct {
+	labels  []string
+	value   float64
+	count   uint64
+	buckets []uint64
+}
+
+// metrics collects the plugin metrics and writes them in the Prometheus text format.
+type metrics struct {
+	mutex         sync.Mutex
+	families      []*metricFamily
+	fetchDuration *metricFamily
+	fetchErrors   *metricFamily
+	parseFailures *metricFamily
+	mergeDuration *metricFamily
+	cacheHits     *metricFamily
+	cacheMisses   *metricFamily
+	requests      *metricFamily
+
+	requestViolations  *metricFamily
+	responsesValidated *metricFamily
+	responseMismatches *metricFamily
+}
+
+func newMetrics() *metrics {
+	collector := &metrics{}
+	collector.fetchDuration = collector.family("swagger_ring_fetch_duration_seconds", "Time spent fetching a source document.", defaultBuckets, "source")
+	collector.fetchErrors = collector.family("swagger_ring_fetch_errors_total", "Failed fetches of a source document by reason.", nil, "source", "reason")
+	collector.parseFailures = collector.family("swagger_ring_parse_failures_total", "Source documents that could not be parsed.", nil, "source", "format")
+	collector.mergeDuration = collector.family("swagger_ring_merge_duration_seconds", "Time spent building the merged document, fetches included.", defaultBuckets)
+	collector.cacheHits = collector.family("swagger_ring_cache_hits_total", "Documents and reports served from the cache.", nil, "document")
+	collector.cacheMisses = collector.family("swagger_ring_cache_misses_total", "Documents and reports built because they were not cached.", nil, "document")
+	collector.requests = collector.family("swagger_ring_requests_total", "Requests served by endpoint and status code.", nil, "endpoint", "code")
+	collector.requestViolations = collector.family("swagger_ring_request_violations_total", "API requests that do not match the merged document.", nil, "source")
+	collector.responsesValidated = collector.family("swagger_ring_responses_validated_total", "API responses checked against the merged document.", nil, "source")
+	collector.responseMismatches = collector.family("swagger_ring_response_mismatches_total", "Mismatches of the API responses with the merged document by kind.", nil, "source", "kind")
+	return collector
+}
+
+func (collector *metrics) family(name, help string, buckets []float64, labels ...string) *metricFamily {
+	family := &metricFamily{name: name, help: help, labels: labels, buckets: buckets, series: make(map[string]*metricSeries)}
+	collector.families = append(collector.families, family)
+	return family
+}
+
+// lookup returns the series of the label values, the caller holds the mutex.
+func (family *metricFamily) lookup(values []string) *metricSeries {
+	key := strings.Join(values, "\x00")
+	series, ok := family.series[key]
+	if !ok {
+		series = &metricSeries{labels: values, buckets: make([]uint64, len(family.buckets))}
+		family.series[key] = series
+	}
+	return series
+}
+
+// inc increments the counter of the label values.
+func (collector *metrics) inc(family *metricFamily, values ...string) {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+	family.lookup(values).value++
+}
+
+// observe adds the duration since the start to the histogram of the label values.
+func (collector *metrics) observe(family *metricFamily, start time.Time, values ...string) {
+	seconds := time.Since(start).Seconds()
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+	series := family.lookup(values)
+	series.value += seconds
+	series.count++
+	for i, bound := range family.buckets {
+		if seconds <= bound {
+			series.buckets[i]++
+		}
+	}
+}
+
+// write writes every metric in the Prometheus text exposition format.
+func (collector *metrics) write(writer io.Writer) {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+	for _, family := range collector.families {
+		kind := "counter"
+		if family.buckets != nil {
+			kind = "histogram"
+		}
+		fmt.Fprintf(writer, "# HELP %v %v\n# TYPE %v %v\n", family.name, family.help, family.name, kind)
+		keys := make([]string, 0, len(family.series))
+		for key := range family.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			series := family.series[key]
+			labels := formatLabels(family.labels, series.labels)
+			if family.buckets == nil {
+				fmt.Fprintf(writer, "%v%v %v\n", family.name, wrapLabels(labels), formatFloat(series.value))
+				continue
+			}
+			for i, bound := range family.buckets {
+				fmt.Fprintf(writer, "%v_bucket%v %d\n", family.name, wrapLabels(append(labels, fmt.Sprintf("le=%q", formatFloat(bound)))), series.buckets[i])
+			}
+			fmt.Fprintf(writer, "%v_bucket%v %d\n", family.name, wrapLabels(append(labels, `le="+Inf"`)), series.count)
+			fmt.Fprintf(writer, "%v_sum%v %v\n", family.name, wrapLabels(labels), formatFloat(series.value))
+			fmt.Fprintf(writer, "%v_count%v %d\n", family.name, wrapLabels(labels), series.count)
+		}
+	}
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatLabels(names, values []string) []string {
+	labels := make([]string, len(names))
+	for i, name := range names {
+		labels[i] = fmt.Sprintf(`%v="%v"`, name, labelValueReplacer.Replace(values[i]))
+	}
+	return labels
+}
+
+func wrapLabels(labels []string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(labels, ",") + "}"
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// serveMetrics writes the metrics for the Prometheus scraper.
+func (swaggerMerger *SwaggerRing) serveMetrics(rw http.ResponseWriter) {
+	rw.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	swaggerMerger.metrics.write(rw)
+}
+
+// statusRecorder remembers the status code written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (recorder *statusRecorder) WriteHeader(status int) {
+	if recorder.status == 0 {
+		recorder.status = status
+	}
+	recorder.ResponseWriter.WriteHeader(status)
+}
+
+func (recorder *statusRecorder) Write(data []byte) (int, error) {
+	if recorder.status == 0 {
+		recorder.status = http.StatusOK
+	}
+	return recorder.ResponseWriter.Write(data)
+}
@@ -0,0 +1,65 @@
+package swagger_ring_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	swagger "github.com/usalko/swagger-ring"
+)
+
+func TestMetrics(t *testing.T) {
+	ctx := context.Background()
+
+	source := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/broken.yaml" {
+			fmt.Fprint(rw, "openapi: [3.0.2\n")
+			return
+		}
+		fmt.Fprint(rw, "openapi: 3.0.2\ninfo:\n  title: Orders\n  version: 1.0.0\npaths: {}\n")
+	}))
+	defer source.Close()
+
+	cfg := swagger.CreateConfig()
+	cfg.Path = "/api/v1/docs"
+	cfg.MetricsPath = "/metrics"
+	cfg.CacheTTL = "1m"
+	cfg.Docs = append(cfg.Docs,
+		&swagger.DocPath{Path: source.URL + "/swagger.yaml", Name: "orders"},
+		&swagger.DocPath{Path: source.URL + "/broken.yaml", Name: "broken"},
+	)
+
+	handler, err := swagger.New(ctx, http.NotFoundHandler(), cfg, "swagger-ring")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for range 2 {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/v1/docs/swagger.yaml", nil))
+	}
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/v1/docs/sources/unknown.yaml", nil))
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest("GET", "/api/v1/docs/metrics", nil))
+	if contentType := rw.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/plain; version=0.0.4") {
+		t.Errorf("expected Prometheus text format, got %v", contentType)
+	}
+	for _, expected := range []string{
+		"# TYPE swagger_ring_fetch_duration_seconds histogram",
+		`swagger_ring_fetch_duration_seconds_count{source="orders"} 1`,
+		`swagger_ring_fetch_duration_seconds_bucket{source="orders",le="+Inf"} 1`,
+		`swagger_ring_fetch_errors_total{source="broken",reason="parse"} 1`,
+		`swagger_ring_parse_failures_total{source="broken",format="yaml"} 1`,
+		"swagger_ring_merge_duration_seconds_count 1",
+		`swagger_ring_cache_hits_total{document="merged"} 1`,
+		`swagger_ring_cache_misses_total{document="merged"} 1`,
+		`swagger_ring_requests_total{endpoint="document",code="200"} 2`,
+		`swagger_ring_requests_total{endpoint="sources",code="404"} 1`,
+	} {
+		if !strings.Contains(rw.Body.String(), expected) {
+			t.Errorf("expected metrics to contain %s, got %s", expected, rw.Body.String())
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package swagger_ring
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MockConfig configures the mock mode, the matching requests are answered
+// from the merged document instead of being passed to the next handler.
+type MockConfig struct {
+	// Sources are the names of the sources whose operations are always mocked.
+	Sources []string `json:"sources"`
+	// Header is the request header that turns the mock on (true) or off (false) for a request, X-Mock by default.
+	Header string `json:"header"`
+}
+
+const defaultMockHeader = "X-Mock"
+
+// maxMockDepth limits how deep a value is generated from a recursive schema.
+const maxMockDepth = 8
+
+// mocker answers requests with the examples of the merged document.
+type mocker struct {
+	header  string
+	sources map[string]bool
+}
+
+func newMocker(config *MockConfig, refs []DocPath) (*mocker, error) {
+	if config == nil {
+		return nil, nil
+	}
+	mock := &mocker{header: config.Header, sources: make(map[string]bool, len(config.Sources))}
+	if mock.header == "" {
+		mock.header = defaultMockHeader
+	}
+	for _, name := range config.Sources {
+		found := false
+		for _, ref := range refs {
+			found = found || ref.Name == name
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown source %v", name)
+		}
+		mock.sources[name] = true
+	}
+	return mock, nil
+}
+
+// enabled tells whether the request may be mocked before the operation is known:
+// the header asks for it or some sources are always mocked.
+func (mock *mocker) enabled(req *http.Request) bool {
+	if mock == nil {
+		return false
+	}
+	if value, err := strconv.ParseBool(req.Header.Get(mock.header)); err == nil {
+		return value
+	}
+	return len(mock.sources) > 0
+}
+
+// mocks tells whether the matched operation is mocked for the request.
+func (mock *mocker) mocks(req *http.Request, match *operationMatch) bool {
+	if value, err := strconv.ParseBool(req.Header.Get(mock.header)); err == nil {
+		return value
+	}
+	return mock.sources[match.source]
+}
+
+// mockResponse is the response generated for an operation.
+type mockResponse struct {
+	status      int
+	contentType string
+	body        []byte
+}
+
+// mockOperation generates the response of the operation: the first success response,
+// its example or a value generated from its schema, in the media type accepted by the request.
+func mockOperation(match *operationMatch, accept string) (*mockResponse, error) {
+	responses, _ := match.operation["responses"].(map[string]any)
+	code := ""
+	for _, key := range sortedKeys(responses) {
+		if strings.HasPrefix(key, "2") && (code == "" || !strings.HasPrefix(code, "2")) {
+			code = key
+		}
+	}
+	if code == "" {
+		if _, ok := responses["default"]; ok {
+			code = "default"
+		} else if keys := sortedKeys(responses); len(keys) > 0 {
+			code = keys[0]
+		}
+	}
+	status, err := strconv.Atoi(strings.ReplaceAll(strings.ToUpper(code), "XX", "00"))
+	if err != nil {
+		status = http.StatusOK
+	}
+	response, _ := resolve(match.document, responses[code])
+	result := &mockResponse{status: status}
+	if response == nil {
+		return result, nil
+	}
+
+	content, _ := response["content"].(map[string]any)
+	if content == nil {
+		// Swagger 2.0 responses have a schema and examples by media type
+		content = make(map[string]any)
+		examples, _ := response["examples"].(map[string]any)
+		for mediaType, example := range examples {
+			content[mediaType] = map[string]any{"example": example}
+		}
+		if schema, ok := response["schema"]; ok && len(content) == 0 {
+			content["application/json"] = map[string]any{"schema": schema}
+		}
+	}
+	mediaType := selectMediaType(content, accept)
+	if mediaType == "" {
+		return result, nil
+	}
+	media, _ := content[mediaType].(map[string]any)
+	value := mediaExample(match.document, media)
+	result.contentType = mediaType
+	result.body, err = encodeMock(mediaType, value)
+	return result, err
+}
+
+// selectMediaType returns the first media type accepted by the request, JSON first for wildcards.
+func selectMediaType(content map[string]any, accept string) string {
+	mediaTypes := sortedKeys(content)
+	if len(mediaTypes) == 0 {
+		return ""
+	}
+	sort.SliceStable(mediaTypes, func(i, j int) bool {
+		return isJSONMediaType(mediaTypes[i]) && !isJSONMediaType(mediaTypes[j])
+	})
+	for _, accepted := range strings.Split(accept, ",") {
+		accepted, _, _ = mime.ParseMediaType(strings.TrimSpace(accepted))
+		for _, mediaType := range mediaTypes {
+			if accepted == "*/*" || accepted == mediaType ||
+				strings.HasSuffix(accepted, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(accepted, "*")) {
+				return mediaType
+			}
+		}
+	}
+	return mediaTypes[0]
+}
+
+func isJSONMediaType(mediaType string) bool {
+	return strings.Contains(mediaType, "json")
+}
+
+// mediaExample returns the example of the media type, the first of its examples,
+// or a value generated from its schema.
+func mediaExample(document, media map[string]any) any {
+	if example, ok := media["example"]; ok {
+		return example
+	}
+	examples, _ := media["examples"].(map[string]any)
+	if keys := sortedKeys(examples); len(keys) > 0 {
+		if example, _ := resolve(document, examples[keys[0]]); example != nil {
+			return example["value"]
+		}
+	}
+	return generateValue(document, media["schema"], 0)
+}
+
+// generateValue builds a value that satisfies the schema, preferring its example, default and enum values.
+func generateValue(document map[string]any, schemaValue any, depth int) any {
+	schema, _ := resolve(document, schemaValue)
+	if schema == nil || depth > maxMockDepth {
+		return nil
+	}
+	if example, ok := schema["example"]; ok {
+		return example
+	}
+	if value, ok := schema["default"]; ok {
+		return value
+	}
+	if values, ok := schema["enum"].([]any); ok && len(values) > 0 {
+		return values[0]
+	}
+	if allOf, ok := schema["allOf"].([]any); ok {
+		result := make(map[string]any)
+		for _, part := range allOf {
+			if object, ok := generateValue(document, part, depth+1).(map[string]any); ok {
+				for key, value := range object {
+					result[key] = value
+				}
+			}
+		}
+		if object, ok := generateObject(document, schema, depth).(map[string]any); ok {
+			for key, value := range object {
+				result[key] = value
+			}
+		}
+		return result
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if alternatives, ok := schema[key].([]any); ok && len(alternatives) > 0 {
+			return generateValue(document, alternatives[0], depth+1)
+		}
+	}
+
+	schemaType, _ := schema["type"].(string)
+	if types, ok := schema["type"].([]any); ok && len(types) > 0 {
+		// OpenAPI 3.1 type lists
+		schemaType, _ = types[0].(string)
+	}
+	switch schemaType {
+	case "object", "":
+		return generateObject(document, schema, depth)
+	case "array":
+		if item := generateValue(document, schema["items"], depth+1); item != nil {
+			return []any{item}
+		}
+		return []any{}
+	case "integer":
+		if minimum, ok := toFloat(schema["minimum"]); ok {
+			return int(minimum)
+		}
+		return 0
+	case "number":
+		if minimum, ok := toFloat(schema["minimum"]); ok {
+			return minimum
+		}
+		return 0.0
+	case "boolean":
+		return true
+	case "string":
+		return generateString(schema)
+	}
+	return nil
+}
+
+func generateObject(document, schema map[string]any, depth int) any {
+	properties, _ := schema["properties"].(map[string]any)
+	if properties == nil && schema["type"] == nil {
+		return nil
+	}
+	result := make(map[string]any, len(properties))
+	for _, name := range sortedKeys(properties) {
+		if value := generateValue(document, properties[name], depth+1); value != nil {
+			result[name] = value
+		}
+	}
+	return result
+}
+
+var mockStrings = map[string]string{
+	"date":      "2026-01-01",
+	"date-time": "2026-01-01T00:00:00Z",
+	"time":      "00:00:00Z",
+	"email":     "user@example.com",
+	"uuid":      "00000000-0000-0000-0000-000000000000",
+	"uri":       "https://example.com",
+	"hostname":  "example.com",
+	"ipv4":      "192.0.2.1",
+	"ipv6":      "2001:db8::1",
+	"byte":      "c3RyaW5n",
+}
+
+func generateString(schema map[string]any) string {
+	format, _ := schema["format"].(string)
+	if value, ok := mockStrings[format]; ok {
+		return value
+	}
+	value := "string"
+	if minLength, ok := toFloat(schema["minLength"]); ok && int(minLength) > len(value) {
+		value += strings.Repeat("s", int(minLength)-len(value))
+	}
+	return value
+}
+
+// encodeMock encodes the value in the media type, JSON unless the media type is YAML or plain text.
+func encodeMock(mediaType string, value any) ([]byte, error) {
+	switch {
+	case strings.Contains(mediaType, "yaml"):
+		return yaml.Marshal(value)
+	case strings.HasPrefix(mediaType, "text/"):
+		if text, ok := value.(string); ok {
+			return []byte(text), nil
+		}
+	}
+	return json.Marshal(value)
+}
+
+// serveMock writes the response generated for the operation.
+func (swaggerMerger *SwaggerRing) serveMock(rw http.ResponseWriter, req *http.Request, match *operationMatch) {
+	response, err := mockOperation(match, req.Header.Get("Accept"))
+	if err != nil {
+		http.Error(rw, fmt.Sprintf("mock issue: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if response.contentType != "" {
+		rw.Header().Set("Content-Type", response.contentType)
+	}
+	rw.Header().Set(swaggerMerger.mock.header, "true")
+	rw.WriteHeader(response.status)
+	rw.Write(response.body)
+}
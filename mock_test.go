@@ -0,0 +1,158 @@
+package swagger_ring_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	swagger "github.com/usalko/swagger-ring"
+)
+
+const mockOrders = `
+openapi: 3.0.2
+info:
+  title: Orders
+  version: 1.0.0
+servers:
+  - url: https://example.com/api/v1
+paths:
+  /orders:
+    post:
+      responses:
+        201:
+          description: Created
+          content:
+            application/json:
+              examples:
+                created:
+                  value: {id: 1}
+  /orders/{id}:
+    get:
+      responses:
+        200:
+          description: OK
+          content:
+            application/json:
+              example: {id: 42, status: paid}
+            application/yaml:
+              example: {id: 42, status: paid}
+`
+
+const mockUsers = `
+openapi: 3.0.2
+info:
+  title: Users
+  version: 1.0.0
+paths:
+  /users/mine:
+    get:
+      responses:
+        200:
+          description: OK
+          content:
+            text/plain:
+              schema:
+                type: string
+                example: me
+  /users/{id}:
+    get:
+      responses:
+        200:
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+components:
+  schemas:
+    User:
+      type: object
+      properties:
+        id:
+          type: integer
+          minimum: 1
+        email:
+          type: string
+          format: email
+        roles:
+          type: array
+          items:
+            type: string
+            enum: [admin, reader]
+`
+
+func TestMock(t *testing.T) {
+	ctx := context.Background()
+
+	source := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/orders.yaml" {
+			fmt.Fprint(rw, mockOrders)
+			return
+		}
+		fmt.Fprint(rw, mockUsers)
+	}))
+	defer source.Close()
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(rw, "next")
+	})
+
+	cfg := swagger.CreateConfig()
+	cfg.Path = "/api/v1/docs"
+	cfg.Mock = &swagger.MockConfig{Sources: []string{"users"}}
+	cfg.Docs = append(cfg.Docs,
+		&swagger.DocPath{Path: source.URL + "/orders.yaml", Name: "orders"},
+		&swagger.DocPath{Path: source.URL + "/users.yaml", Name: "users"},
+	)
+
+	handler, err := swagger.New(ctx, next, cfg, "swagger-ring")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tt := []struct {
+		name     string
+		method   string
+		path     string
+		mock     string
+		accept   string
+		status   int
+		expected string
+	}{
+		{name: "not mocked source", method: "GET", path: "/api/v1/orders/42", status: http.StatusOK, expected: "next"},
+		{name: "example", method: "GET", path: "/api/v1/orders/42", mock: "true", status: http.StatusOK, expected: `{"id":42,"status":"paid"}`},
+		{name: "accepted media type", method: "GET", path: "/api/v1/orders/42", mock: "true", accept: "application/yaml", status: http.StatusOK, expected: "id: 42\nstatus: paid\n"},
+		{name: "named example", method: "POST", path: "/orders", mock: "true", status: http.StatusCreated, expected: `{"id":1}`},
+		{name: "concrete path", method: "GET", path: "/users/mine", status: http.StatusOK, expected: "me"},
+		{name: "generated", method: "GET", path: "/users/7", status: http.StatusOK, expected: `{"email":"user@example.com","id":1,"roles":["admin"]}`},
+		{name: "turned off", method: "GET", path: "/users/7", mock: "false", status: http.StatusOK, expected: "next"},
+		{name: "unknown method", method: "DELETE", path: "/users/7", status: http.StatusOK, expected: "next"},
+		{name: "unknown path", method: "GET", path: "/billing", mock: "true", status: http.StatusOK, expected: "next"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			if tc.mock != "" {
+				req.Header.Set("X-Mock", tc.mock)
+			}
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+			if rw.Code != tc.status {
+				t.Errorf("expected status %d, got %d", tc.status, rw.Code)
+			}
+			if rw.Body.String() != tc.expected {
+				t.Errorf("expected response %s, got %s", tc.expected, rw.Body.String())
+			}
+		})
+	}
+
+	cfg.Mock.Sources = []string{"billing"}
+	if _, err := swagger.New(ctx, next, cfg, "swagger-ring"); err == nil {
+		t.Error("expected error for an unknown mocked source, got nil")
+	}
+}
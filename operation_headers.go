@@ -0,0 +1,66 @@
+package swagger_ring
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// OperationHeadersConfig names the request headers that tell the next handler
+// which operation of the merged document the request matches.
+type OperationHeadersConfig struct {
+	// OperationID is the header with the operationId, X-Operation-Id by default.
+	OperationID string `json:"operationId"`
+	// Source is the header with the name of the source of the operation, X-Api-Source by default.
+	Source string `json:"source"`
+	// PathTemplate is the header with the path template of the operation, X-Path-Template by default.
+	PathTemplate string `json:"pathTemplate"`
+}
+
+// operationHeaders tags the API requests with their operation.
+type operationHeaders struct {
+	operationID  string
+	source       string
+	pathTemplate string
+}
+
+func newOperationHeaders(config *OperationHeadersConfig) (*operationHeaders, error) {
+	if config == nil {
+		return nil, nil
+	}
+	headers := &operationHeaders{operationID: config.OperationID, source: config.Source, pathTemplate: config.PathTemplate}
+	for _, header := range []struct {
+		name     *string
+		fallback string
+	}{
+		{&headers.operationID, "X-Operation-Id"},
+		{&headers.source, "X-Api-Source"},
+		{&headers.pathTemplate, "X-Path-Template"},
+	} {
+		if *header.name == "" {
+			*header.name = header.fallback
+		}
+		*header.name = http.CanonicalHeaderKey(*header.name)
+	}
+	if headers.operationID == headers.source || headers.operationID == headers.pathTemplate || headers.source == headers.pathTemplate {
+		return nil, fmt.Errorf("header names must be different")
+	}
+	return headers, nil
+}
+
+// set replaces the headers of the request with the ones of the matched operation.
+// The values sent by the client are always removed, so the next handler can trust them.
+func (headers *operationHeaders) set(req *http.Request, match *operationMatch) {
+	req.Header.Del(headers.operationID)
+	req.Header.Del(headers.source)
+	req.Header.Del(headers.pathTemplate)
+	if match == nil {
+		return
+	}
+	if operationID, ok := match.operation["operationId"].(string); ok && operationID != "" {
+		req.Header.Set(headers.operationID, operationID)
+	}
+	if match.source != "" {
+		req.Header.Set(headers.source, match.source)
+	}
+	req.Header.Set(headers.pathTemplate, match.path)
+}
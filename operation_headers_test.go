@@ -0,0 +1,60 @@
+package swagger_ring_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	swagger "github.com/usalko/swagger-ring"
+)
+
+func TestOperationHeaders(t *testing.T) {
+	ctx := context.Background()
+
+	source := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(rw, "openapi: 3.0.2\ninfo:\n  title: Orders\n  version: 1.0.0\npaths:\n  /orders/{id}:\n    get:\n      operationId: getOrder\n      responses: {}\n")
+	}))
+	defer source.Close()
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(rw, "%v|%v|%v", req.Header.Get("X-Operation-Id"), req.Header.Get("X-Api-Source"), req.Header.Get("X-Path-Template"))
+	})
+
+	cfg := swagger.CreateConfig()
+	cfg.Path = "/api/v1/docs"
+	cfg.OperationHeaders = &swagger.OperationHeadersConfig{}
+	cfg.Docs = append(cfg.Docs, &swagger.DocPath{Path: source.URL + "/swagger.yaml", Name: "orders"})
+
+	handler, err := swagger.New(ctx, next, cfg, "swagger-ring")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tt := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{name: "operation", path: "/orders/42", expected: "getOrder|orders|/orders/{id}"},
+		{name: "no operation", path: "/health", expected: "||"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tc.path, nil)
+			// Spoofed by the client
+			req.Header.Set("X-Operation-Id", "deleteEverything")
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+			if rw.Body.String() != tc.expected {
+				t.Errorf("expected headers %s, got %s", tc.expected, rw.Body.String())
+			}
+		})
+	}
+
+	cfg.OperationHeaders.Source = "x-operation-id"
+	if _, err := swagger.New(ctx, next, cfg, "swagger-ring"); err == nil {
+		t.Error("expected error for the same header names, got nil")
+	}
+}
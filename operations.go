@@ -0,0 +1,207 @@
+package swagger_ring
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultOperationIndexTTL is how long the operations of the merged document are reused
+// to match requests when the document cache is disabled.
+const defaultOperationIndexTTL = time.Minute
+
+// operationMatch is the operation of the merged document that matches a request.
+type operationMatch struct {
+	// path is the path template of the operation, e.g. /orders/{id}
+	path   string
+	method string
+	// pointer is the JSON pointer of the operation in the merged document
+	pointer   string
+	item      map[string]any
+	operation map[string]any
+	// params are the values of the path parameters
+	params map[string]string
+	// source is the name of the source that defined the operation
+	source   string
+	document map[string]any
+}
+
+// operationRoute matches the requests of an operation.
+type operationRoute struct {
+	// prefixes are the base paths of the servers
+	prefixes  []string
+	path      string
+	method    string
+	pattern   *regexp.Regexp
+	names     []string
+	item      map[string]any
+	operation map[string]any
+	source    string
+}
+
+// operationIndex keeps the routes of the operations of the merged document.
+// The routes are rebuilt by one request at a time, and only the first build is waited for:
+// later ones run in the background while the other requests keep using the previous routes.
+type operationIndex struct {
+	mutex      sync.Mutex
+	ttl        time.Duration
+	expires    time.Time
+	refreshing bool
+	// ready is closed once the first routes are built
+	ready    chan struct{}
+	routes   []*operationRoute
+	document map[string]any
+}
+
+func newOperationIndex(ttl time.Duration) *operationIndex {
+	return &operationIndex{ttl: ttl, ready: make(chan struct{})}
+}
+
+// buildOperationRoutes returns the routes of every operation, the most specific paths first.
+// The paths are matched with and without the base paths of the servers.
+func buildOperationRoutes(merged *mergedDocument) []*operationRoute {
+	prefixes := []string{""}
+	servers, _ := merged.document["servers"].([]any)
+	for _, serverValue := range servers {
+		server, _ := serverValue.(map[string]any)
+		serverURL, _ := server["url"].(string)
+		if parsed, err := url.Parse(serverURL); err == nil && !strings.Contains(parsed.Path, "{") {
+			if prefix := strings.TrimSuffix(parsed.Path, "/"); prefix != "" {
+				prefixes = append(prefixes, prefix)
+			}
+		}
+	}
+	if basePath, ok := merged.document["basePath"].(string); ok && strings.TrimSuffix(basePath, "/") != "" {
+		prefixes = append(prefixes, strings.TrimSuffix(basePath, "/"))
+	}
+
+	routes := make([]*operationRoute, 0)
+	paths, _ := merged.document["paths"].(map[string]any)
+	for _, path := range sortedKeys(paths) {
+		item, _ := paths[path].(map[string]any)
+		names := make([]string, 0)
+		for _, parameter := range pathParameterPattern.FindAllStringSubmatch(path, -1) {
+			names = append(names, parameter[1])
+		}
+		literals := pathParameterPattern.Split(path, -1)
+		expression := ""
+		for i, literal := range literals {
+			expression += regexp.QuoteMeta(literal)
+			if i < len(literals)-1 {
+				expression += "([^/]+)"
+			}
+		}
+		quoted := make([]string, len(prefixes))
+		for i, prefix := range prefixes {
+			quoted[i] = regexp.QuoteMeta(prefix)
+		}
+		pattern := regexp.MustCompile("^(?:" + strings.Join(quoted, "|") + ")" + expression + "$")
+		for _, method := range httpMethods {
+			operation, ok := item[method].(map[string]any)
+			if !ok {
+				continue
+			}
+			pointer := joinPointer(joinPointer("/paths", path), method)
+			routes = append(routes, &operationRoute{
+				prefixes:  prefixes,
+				path:      path,
+				method:    strings.ToUpper(method),
+				pattern:   pattern,
+				names:     names,
+				item:      item,
+				operation: operation,
+				source:    merged.sourceOf(pointer),
+			})
+		}
+	}
+	// Concrete paths win over templated ones, /orders/mine before /orders/{id}
+	sort.SliceStable(routes, func(i, j int) bool {
+		if len(routes[i].names) != len(routes[j].names) {
+			return len(routes[i].names) < len(routes[j].names)
+		}
+		return len(routes[i].path) > len(routes[j].path)
+	})
+	return routes
+}
+
+// operationRoutes returns the routes of the merged document operations, refreshed when they expire.
+func (swaggerMerger *SwaggerRing) operationRoutes() ([]*operationRoute, map[string]any) {
+	index := swaggerMerger.operations
+	index.mutex.Lock()
+	refresh := !index.refreshing && time.Now().After(index.expires)
+	if refresh {
+		index.refreshing = true
+	}
+	index.mutex.Unlock()
+	if refresh {
+		select {
+		case <-index.ready:
+			go swaggerMerger.refreshOperations()
+		default:
+			swaggerMerger.refreshOperations()
+		}
+	}
+	<-index.ready
+
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+	return index.routes, index.document
+}
+
+// refreshOperations rebuilds the routes, the previous ones are kept when the merged document cannot be built.
+func (swaggerMerger *SwaggerRing) refreshOperations() {
+	index := swaggerMerger.operations
+	merged, err := swaggerMerger.currentMergedDocument()
+	var routes []*operationRoute
+	if err == nil {
+		routes = buildOperationRoutes(merged)
+	} else {
+		swaggerMerger.logger.Warn("operations not refreshed", "source", "merged", "error", err)
+	}
+
+	index.mutex.Lock()
+	defer index.mutex.Unlock()
+	if err == nil {
+		index.routes, index.document = routes, merged.document
+	}
+	index.expires = time.Now().Add(index.ttl)
+	index.refreshing = false
+	select {
+	case <-index.ready:
+	default:
+		close(index.ready)
+	}
+}
+
+// matchOperation returns the operation of the merged document that matches the request, or nil.
+func (swaggerMerger *SwaggerRing) matchOperation(req *http.Request) *operationMatch {
+	routes, document := swaggerMerger.operationRoutes()
+	for _, route := range routes {
+		if route.method != req.Method {
+			continue
+		}
+		values := route.pattern.FindStringSubmatch(req.URL.Path)
+		if values == nil {
+			continue
+		}
+		params := make(map[string]string, len(route.names))
+		for i, name := range route.names {
+			params[name], _ = url.PathUnescape(values[i+1])
+		}
+		return &operationMatch{
+			path:      route.path,
+			method:    route.method,
+			pointer:   joinPointer(joinPointer("/paths", route.path), strings.ToLower(route.method)),
+			item:      route.item,
+			operation: route.operation,
+			params:    params,
+			source:    route.source,
+			document:  document,
+		}
+	}
+	return nil
+}
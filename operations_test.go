@@ -0,0 +1,86 @@
+package swagger_ring_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	swagger "github.com/usalko/swagger-ring"
+)
+
+func TestOperationRefresh(t *testing.T) {
+	ctx := context.Background()
+
+	var version atomic.Int32
+	version.Store(1)
+	var hung atomic.Bool
+	hanging, release := make(chan struct{}, 1), make(chan struct{})
+	source := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if hung.Load() {
+			hanging <- struct{}{}
+			<-release
+		}
+		fmt.Fprintf(rw, "openapi: 3.0.2\ninfo:\n  title: Orders\n  version: 1.0.0\npaths:\n  /v%d/orders:\n    get:\n      responses: {}\n", version.Load())
+	}))
+	defer source.Close()
+	defer close(release)
+
+	cfg := swagger.CreateConfig()
+	cfg.Path = "/api/v1/docs"
+	cfg.CacheTTL = "10ms"
+	cfg.Coverage = &swagger.CoverageConfig{}
+	cfg.OperationHeaders = &swagger.OperationHeadersConfig{}
+	cfg.Docs = append(cfg.Docs, &swagger.DocPath{Path: source.URL + "/orders.yaml", Name: "orders"})
+
+	var template atomic.Value
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		template.Store(req.Header.Get("X-Path-Template"))
+	})
+	handler, err := swagger.New(ctx, next, cfg, "swagger-ring")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	serve := func(path string) (string, time.Duration) {
+		start := time.Now()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+		return template.Load().(string), time.Since(start)
+	}
+
+	if matched, _ := serve("/v1/orders"); matched != "/v1/orders" {
+		t.Fatalf("expected the first request to wait for the routes, got %q", matched)
+	}
+
+	// A hung source does not hold the API requests, the previous routes are used meanwhile
+	hung.Store(true)
+	time.Sleep(20 * time.Millisecond)
+	serve("/v1/orders")
+	select {
+	case <-hanging:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the routes to be refreshed in the background")
+	}
+	for i := 0; i < 3; i++ {
+		matched, elapsed := serve("/v1/orders")
+		if matched != "/v1/orders" || elapsed > 500*time.Millisecond {
+			t.Errorf("expected the previous routes without waiting, got %q after %v", matched, elapsed)
+		}
+	}
+
+	version.Store(2)
+	hung.Store(false)
+	release <- struct{}{}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if matched, _ := serve("/v2/orders"); matched == "/v2/orders" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the routes to be refreshed once the source answers")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,179 @@
+package swagger_ring
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Overlay is an OpenAPI Overlay 1.0 document (https://spec.openapis.org/overlay/v1.0.0.html).
+// The document is either loaded from a file, given inline as YAML/JSON content
+// or described directly by its actions.
+type Overlay struct {
+	// File is a path to an overlay document on the local file system.
+	File string `json:"file"`
+	// Content is an inline overlay document in YAML or JSON format.
+	Content string `json:"content"`
+	// Actions are inline overlay actions.
+	Actions []*OverlayAction `json:"actions"`
+}
+
+// OverlayAction is a single overlay action.
+type OverlayAction struct {
+	// Target is a JSONPath expression selecting the nodes to change.
+	Target string `json:"target" yaml:"target"`
+	// Description is a free form description of the action.
+	Description string `json:"description" yaml:"description"`
+	// Update is merged into every selected node.
+	Update any `json:"update" yaml:"update"`
+	// Remove deletes every selected node.
+	Remove bool `json:"remove" yaml:"remove"`
+}
+
+// overlayDocument is a loaded overlay with compiled targets.
+type overlayDocument struct {
+	Overlay string           `yaml:"overlay"`
+	Actions []*OverlayAction `yaml:"actions"`
+
+	name    string
+	targets []*jsonPath
+}
+
+// loadOverlays loads and compiles the configured overlays.
+func loadOverlays(overlays []*Overlay) ([]*overlayDocument, error) {
+	result := make([]*overlayDocument, 0, len(overlays))
+	for i, overlay := range overlays {
+		document, err := loadOverlay(overlay)
+		if err != nil {
+			return nil, fmt.Errorf("overlay #%d: %w", i, err)
+		}
+		result = append(result, document)
+	}
+	return result, nil
+}
+
+func loadOverlay(overlay *Overlay) (*overlayDocument, error) {
+	document := &overlayDocument{name: "inline"}
+	content := overlay.Content
+	if overlay.File != "" {
+		data, err := os.ReadFile(overlay.File)
+		if err != nil {
+			return nil, fmt.Errorf("read overlay file: %w", err)
+		}
+		content = string(data)
+		document.name = overlay.File
+	}
+	if strings.TrimSpace(content) != "" {
+		// YAML is a superset of JSON, so one decoder reads both formats
+		if err := yaml.Unmarshal([]byte(content), document); err != nil {
+			return nil, fmt.Errorf("wrong overlay document format: %w", err)
+		}
+		if document.Overlay != "" && !strings.HasPrefix(document.Overlay, "1.") {
+			return nil, fmt.Errorf("unsupported overlay version %v", document.Overlay)
+		}
+	}
+	document.Actions = append(document.Actions, overlay.Actions...)
+	for i, action := range document.Actions {
+		if action.Update == nil && !action.Remove {
+			return nil, fmt.Errorf("action #%d (%v) has neither update nor remove", i, action.Target)
+		}
+		target, err := compileJSONPath(action.Target)
+		if err != nil {
+			return nil, fmt.Errorf("action #%d: %w", i, err)
+		}
+		action.Update = normalizeDocument(action.Update)
+		document.targets = append(document.targets, target)
+	}
+	return document, nil
+}
+
+// applyOverlays applies the overlays to the document in order.
+func applyOverlays(document map[string]any, overlays []*overlayDocument) error {
+	for _, overlay := range overlays {
+		for i, action := range overlay.Actions {
+			if err := applyOverlayAction(document, overlay.targets[i], action); err != nil {
+				return fmt.Errorf("%v action %v: %w", overlay.name, action.Target, err)
+			}
+		}
+	}
+	return nil
+}
+
+func applyOverlayAction(document map[string]any, target *jsonPath, action *OverlayAction) error {
+	matches := target.Select(document)
+	if action.Remove {
+		// Remove the deepest and the last array elements first so the remaining pointers stay valid
+		pointers := make([]string, 0, len(matches))
+		for _, match := range matches {
+			pointers = append(pointers, match.Pointer)
+		}
+		sort.Slice(pointers, func(i, j int) bool { return comparePointers(pointers[i], pointers[j]) > 0 })
+		for _, pointer := range pointers {
+			if err := removePointer(document, pointer); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, match := range matches {
+		switch node := match.Value.(type) {
+		case map[string]any:
+			update, ok := action.Update.(map[string]any)
+			if !ok {
+				return fmt.Errorf("update for object %v must be an object", match.Pointer)
+			}
+			mergeOverlayUpdate(node, deepCopy(update).(map[string]any))
+		case []any:
+			if err := setPointer(document, match.Pointer, append(node, deepCopy(action.Update))); err != nil {
+				return err
+			}
+		default:
+			if err := setPointer(document, match.Pointer, deepCopy(action.Update)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// mergeOverlayUpdate merges the update into the target as the Overlay specification requires:
+// objects are merged recursively, arrays are appended and other values are replaced.
+func mergeOverlayUpdate(target, update map[string]any) {
+	for key, value := range update {
+		switch current := target[key].(type) {
+		case map[string]any:
+			if updateMap, ok := value.(map[string]any); ok {
+				mergeOverlayUpdate(current, updateMap)
+				continue
+			}
+		case []any:
+			if updateSlice, ok := value.([]any); ok {
+				target[key] = append(current, updateSlice...)
+				continue
+			}
+		}
+		target[key] = value
+	}
+}
+
+// deepCopy returns a copy of the document tree that shares no maps or slices with the original.
+func deepCopy(value any) any {
+	switch node := value.(type) {
+	case map[string]any:
+		result := make(map[string]any, len(node))
+		for key, child := range node {
+			result[key] = deepCopy(child)
+		}
+		return result
+	case []any:
+		result := make([]any, len(node))
+		for i, child := range node {
+			result[i] = deepCopy(child)
+		}
+		return result
+	}
+	return value
+}
@@ -0,0 +1,105 @@
+package swagger_ring_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	swagger "github.com/usalko/swagger-ring"
+)
+
+const overlaySource = `
+openapi: 3.0.2
+info:
+  title: Users
+  version: 1.0.0
+paths:
+  /users:
+    get:
+      summary: List users
+      responses:
+        200:
+          description: OK
+    delete:
+      summary: Drop users
+      x-internal: true
+      responses:
+        204:
+          description: Deleted
+`
+
+func TestOverlays(t *testing.T) {
+	ctx := context.Background()
+
+	source := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(rw, overlaySource)
+	}))
+	defer source.Close()
+
+	overlayFile := filepath.Join(t.TempDir(), "overlay.yaml")
+	err := os.WriteFile(overlayFile, []byte(`
+overlay: 1.0.0
+info:
+  title: Hide internal operations
+  version: 1.0.0
+actions:
+  - target: $.paths.*[?(@.x-internal == true)]
+    remove: true
+`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := swagger.CreateConfig()
+	cfg.Path = "/api/v1/docs"
+	cfg.Docs = append(cfg.Docs, &swagger.DocPath{
+		Path: source.URL + "/swagger.yaml",
+		Overlays: []*swagger.Overlay{
+			{File: overlayFile},
+			{Actions: []*swagger.OverlayAction{{
+				Target: "$.paths['/users'].get",
+				Update: map[string]any{"description": "Returns every user"},
+			}}},
+		},
+	})
+	cfg.Overlays = append(cfg.Overlays, &swagger.Overlay{
+		Content: `{"overlay": "1.0.0", "actions": [{"target": "$.info", "update": {"title": "Unified API"}}]}`,
+	})
+
+	handler, err := swagger.New(ctx, http.NotFoundHandler(), cfg, "swagger-ring")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest("GET", "/api/v1/docs/swagger.json", nil))
+
+	var merged struct {
+		Info struct {
+			Title string `json:"title"`
+		} `json:"info"`
+		Paths map[string]map[string]map[string]any `json:"paths"`
+	}
+	if err := json.Unmarshal(rw.Body.Bytes(), &merged); err != nil {
+		t.Fatalf("expected JSON document, got %v", err)
+	}
+	if merged.Info.Title != "Unified API" {
+		t.Errorf("expected title from the merged document overlay, got %q", merged.Info.Title)
+	}
+	if _, ok := merged.Paths["/users"]["delete"]; ok {
+		t.Error("expected internal operation to be removed")
+	}
+	if description := merged.Paths["/users"]["get"]["description"]; description != "Returns every user" {
+		t.Errorf("expected description from the source overlay, got %v", description)
+	}
+
+	cfg.Overlays = []*swagger.Overlay{{Actions: []*swagger.OverlayAction{{Target: "paths"}}}}
+	if _, err := swagger.New(ctx, http.NotFoundHandler(), cfg, "swagger-ring"); err == nil {
+		t.Error("expected error for invalid overlay action, got nil")
+	}
+}
@@ -0,0 +1,153 @@
+package swagger_ring
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// sourceExtension is the extension that names the source of an operation or a component.
+const sourceExtension = "x-swagger-ring-source"
+
+// ProvenanceEntry tells which sources defined a JSON pointer of the merged document.
+type ProvenanceEntry struct {
+	// Sources are the names of the documents that defined the value. Objects and arrays
+	// combined from several documents list all of them, the last one wins for scalar values.
+	Sources []string `json:"sources"`
+	// Overwritten are the names of the documents whose value at this pointer was replaced.
+	Overwritten []string `json:"overwritten,omitempty"`
+}
+
+// provenance records the sources of the merged document pointers while merging.
+// A nil provenance records nothing.
+type provenance struct {
+	source  string
+	entries map[string]*ProvenanceEntry
+	// children are the recorded pointers right below a pointer, so a subtree is removed without a full scan
+	children map[string]map[string]bool
+}
+
+func newProvenance() *provenance {
+	return &provenance{entries: make(map[string]*ProvenanceEntry), children: make(map[string]map[string]bool)}
+}
+
+// set records the entry of the pointer and indexes it below its parent.
+func (trace *provenance) set(pointer string, entry *ProvenanceEntry) {
+	if _, ok := trace.entries[pointer]; !ok && pointer != "" {
+		parent := pointer[:strings.LastIndex(pointer, "/")]
+		if trace.children[parent] == nil {
+			trace.children[parent] = make(map[string]bool)
+		}
+		trace.children[parent][pointer] = true
+	}
+	trace.entries[pointer] = entry
+}
+
+// removeBelow removes the entries of every pointer below the pointer.
+func (trace *provenance) removeBelow(pointer string) {
+	for child := range trace.children[pointer] {
+		trace.removeBelow(child)
+		delete(trace.entries, child)
+	}
+	delete(trace.children, pointer)
+}
+
+// contribute records that the current source was merged into the object or the array at the pointer.
+func (trace *provenance) contribute(pointer string) {
+	if trace == nil {
+		return
+	}
+	entry, ok := trace.entries[pointer]
+	if !ok {
+		trace.set(pointer, &ProvenanceEntry{Sources: []string{trace.source}})
+		return
+	}
+	for _, source := range entry.Sources {
+		if source == trace.source {
+			return
+		}
+	}
+	entry.Sources = append(entry.Sources, trace.source)
+}
+
+// replace records that the current source set the value at the pointer, overwriting the previous one.
+func (trace *provenance) replace(pointer string, value any) {
+	if trace == nil {
+		return
+	}
+	entry := &ProvenanceEntry{Sources: []string{trace.source}}
+	if previous, ok := trace.entries[pointer]; ok {
+		entry.Overwritten = append(append(entry.Overwritten, previous.Overwritten...), previous.Sources...)
+		trace.removeBelow(pointer)
+	}
+	trace.set(pointer, entry)
+	trace.define(pointer, value)
+}
+
+// define records the current source for every value below the pointer.
+func (trace *provenance) define(pointer string, value any) {
+	if trace == nil {
+		return
+	}
+	for _, child := range children(jsonPathMatch{Pointer: pointer, Value: value}) {
+		trace.set(child.Pointer, &ProvenanceEntry{Sources: []string{trace.source}})
+		trace.define(child.Pointer, child.Value)
+	}
+}
+
+// sourceOf returns the name of the source that defined the pointer or its closest parent.
+func (merged *mergedDocument) sourceOf(pointer string) string {
+	for {
+		if entry, ok := merged.provenance.entries[pointer]; ok {
+			return entry.Sources[len(entry.Sources)-1]
+		}
+		index := strings.LastIndex(pointer, "/")
+		if index < 0 {
+			return ""
+		}
+		pointer = pointer[:index]
+	}
+}
+
+// annotateSources adds the source extension to every operation and component.
+func (merged *mergedDocument) annotateSources() {
+	paths, _ := merged.document["paths"].(map[string]any)
+	for path, itemValue := range paths {
+		item, _ := itemValue.(map[string]any)
+		for _, method := range httpMethods {
+			if operation, ok := item[method].(map[string]any); ok {
+				operation[sourceExtension] = merged.sourceOf(joinPointer(joinPointer("/paths", path), method))
+			}
+		}
+	}
+	components, _ := merged.document["components"].(map[string]any)
+	for kind, groupValue := range components {
+		group, _ := groupValue.(map[string]any)
+		for name, componentValue := range group {
+			if component, ok := componentValue.(map[string]any); ok {
+				component[sourceExtension] = merged.sourceOf(joinPointer(joinPointer("/components", kind), name))
+			}
+		}
+	}
+}
+
+// GetProvenance returns the sources of every JSON pointer of the merged document as JSON.
+func (swaggerMerger *SwaggerRing) GetProvenance() (string, error) {
+	return swaggerMerger.cache.get("provenance", func() (string, error) {
+		merged, err := swaggerMerger.currentMergedDocument()
+		if err != nil {
+			return "", err
+		}
+		entries := make(map[string]*ProvenanceEntry, len(merged.provenance.entries))
+		for pointer, entry := range merged.provenance.entries {
+			// Skip the values removed by the overlays of the merged document
+			if _, ok := lookupPointer(merged.document, pointer); ok {
+				entries[pointer] = entry
+			}
+		}
+		report, err := json.Marshal(entries)
+		if err != nil {
+			return "", err
+		}
+		return string(report), nil
+	})
+}
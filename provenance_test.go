@@ -0,0 +1,116 @@
+package swagger_ring_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	swagger "github.com/usalko/swagger-ring"
+)
+
+func TestProvenance(t *testing.T) {
+	ctx := context.Background()
+
+	source := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/users.yaml":
+			fmt.Fprint(rw, "openapi: 3.0.2\ninfo:\n  title: Users\npaths:\n  /users:\n    get:\n      summary: Users\n      responses: {}\ncomponents:\n  schemas:\n    User:\n      type: object\n")
+		case "/orders.yaml":
+			fmt.Fprint(rw, "openapi: 3.0.2\ninfo:\n  title: Orders\npaths:\n  /orders:\n    get:\n      summary: Orders\n      responses: {}\n")
+		}
+	}))
+	defer source.Close()
+	users, orders := "users", "orders"
+
+	cfg := swagger.CreateConfig()
+	cfg.Path = "/api/v1/docs"
+	cfg.AnnotateSources = true
+	cfg.Docs = append(cfg.Docs,
+		&swagger.DocPath{Path: strings.Replace(source.URL, "://", "://reader:secret@", 1) + "/users.yaml", Name: users},
+		&swagger.DocPath{Path: source.URL + "/orders.yaml", Name: orders},
+	)
+
+	handler, err := swagger.New(ctx, http.NotFoundHandler(), cfg, "swagger-ring")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest("GET", "/api/v1/docs/provenance", nil))
+	if strings.Contains(rw.Body.String(), "secret") {
+		t.Errorf("expected the source URLs to stay out of the report, got %s", rw.Body.String())
+	}
+	var entries map[string]*swagger.ProvenanceEntry
+	if err := json.Unmarshal(rw.Body.Bytes(), &entries); err != nil {
+		t.Fatalf("expected JSON report, got %v", err)
+	}
+
+	tt := []struct {
+		pointer  string
+		expected swagger.ProvenanceEntry
+	}{
+		{pointer: "/paths", expected: swagger.ProvenanceEntry{Sources: []string{users, orders}}},
+		{pointer: "/paths/~1users/get/summary", expected: swagger.ProvenanceEntry{Sources: []string{users}}},
+		{pointer: "/paths/~1orders/get", expected: swagger.ProvenanceEntry{Sources: []string{orders}}},
+		{pointer: "/info/title", expected: swagger.ProvenanceEntry{Sources: []string{orders}, Overwritten: []string{users}}},
+		{pointer: "/components/schemas/User/type", expected: swagger.ProvenanceEntry{Sources: []string{users}}},
+	}
+	for _, tc := range tt {
+		if entry := entries[tc.pointer]; entry == nil || !reflect.DeepEqual(*entry, tc.expected) {
+			t.Errorf("expected %+v at %v, got %+v", tc.expected, tc.pointer, entry)
+		}
+	}
+
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest("GET", "/api/v1/docs/swagger.json", nil))
+	var document struct {
+		Paths      map[string]map[string]map[string]any `json:"paths"`
+		Components map[string]map[string]map[string]any `json:"components"`
+	}
+	if err := json.Unmarshal(rw.Body.Bytes(), &document); err != nil {
+		t.Fatalf("expected JSON document, got %v", err)
+	}
+	if annotation := document.Paths["/orders"]["get"]["x-swagger-ring-source"]; annotation != orders {
+		t.Errorf("expected operation annotated with %v, got %v", orders, annotation)
+	}
+	if annotation := document.Components["schemas"]["User"]["x-swagger-ring-source"]; annotation != users {
+		t.Errorf("expected component annotated with %v, got %v", users, annotation)
+	}
+}
+
+// BenchmarkMergeSharedSchemas merges two sources that define the same schemas, so every schema is overwritten.
+func BenchmarkMergeSharedSchemas(b *testing.B) {
+	for _, schemas := range []int{250, 1000} {
+		b.Run(fmt.Sprint(schemas), func(b *testing.B) {
+			var document strings.Builder
+			document.WriteString("openapi: 3.0.2\ninfo:\n  title: Shared\n  version: 1.0.0\npaths: {}\ncomponents:\n  schemas:\n")
+			for i := 0; i < schemas; i++ {
+				fmt.Fprintf(&document, "    Schema%d:\n      type: object\n      properties:\n        id:\n          type: integer\n        name:\n          type: string\n", i)
+			}
+			source := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+				fmt.Fprint(rw, document.String())
+			}))
+			defer source.Close()
+
+			cfg := swagger.CreateConfig()
+			cfg.Docs = append(cfg.Docs, &swagger.DocPath{Path: source.URL + "/first.yaml"}, &swagger.DocPath{Path: source.URL + "/second.yaml"})
+			cfg.Log = &swagger.LogConfig{Level: "error"}
+			handler, err := swagger.New(context.Background(), http.NotFoundHandler(), cfg, "swagger-ring")
+			if err != nil {
+				b.Fatal(err)
+			}
+			ring := handler.(*swagger.SwaggerRing)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := ring.GetProvenance(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,318 @@
+package swagger_ring
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// RequestValidationConfig configures the validation of the API requests against the merged document.
+type RequestValidationConfig struct {
+	// Mode is enforce (default), rejecting invalid requests, or report, logging them and forwarding anyway.
+	Mode string `json:"mode"`
+	// MaxBodySize is the largest request body validated in bytes, 1 MiB by default. Larger bodies are forwarded unchecked.
+	MaxBodySize int64 `json:"maxBodySize"`
+}
+
+// RequestViolation is a part of a request that does not match the operation.
+type RequestViolation struct {
+	// In is the location of the invalid value: path, query, header, cookie or body.
+	In string `json:"in"`
+	// Name is the name of the invalid parameter.
+	Name string `json:"name,omitempty"`
+	// Pointer is the JSON pointer of the invalid value in the body.
+	Pointer string `json:"pointer,omitempty"`
+	Message string `json:"message"`
+}
+
+func (violation *RequestViolation) String() string {
+	location := strings.TrimSpace(violation.In + " " + violation.Name)
+	return fmt.Sprintf("%v%v: %v", location, violation.Pointer, violation.Message)
+}
+
+// RequestProblem is the problem+json (RFC 9457) response to an invalid request.
+type RequestProblem struct {
+	Type       string              `json:"type"`
+	Title      string              `json:"title"`
+	Status     int                 `json:"status"`
+	Detail     string              `json:"detail"`
+	Operation  string              `json:"operation"`
+	Violations []*RequestViolation `json:"violations"`
+}
+
+const (
+	requestValidationEnforce = "enforce"
+	requestValidationReport  = "report"
+
+	defaultMaxBodySize = 1 << 20
+)
+
+// ignoredHeaders are described by other parts of the operation, OpenAPI ignores them as header parameters.
+var ignoredHeaders = map[string]bool{"Accept": true, "Content-Type": true, "Authorization": true}
+
+// requestValidator checks the API requests against the operations of the merged document.
+type requestValidator struct {
+	report      bool
+	maxBodySize int64
+}
+
+func newRequestValidator(config *RequestValidationConfig) (*requestValidator, error) {
+	if config == nil {
+		return nil, nil
+	}
+	validator := &requestValidator{maxBodySize: config.MaxBodySize}
+	switch config.Mode {
+	case "", requestValidationEnforce:
+	case requestValidationReport:
+		validator.report = true
+	default:
+		return nil, fmt.Errorf("invalid mode %q", config.Mode)
+	}
+	if validator.maxBodySize <= 0 {
+		validator.maxBodySize = defaultMaxBodySize
+	}
+	return validator, nil
+}
+
+// validateRequest returns the violations of the request. The body is read and restored for the next handler.
+func (validator *requestValidator) validateRequest(req *http.Request, match *operationMatch) []*RequestViolation {
+	violations := make([]*RequestViolation, 0)
+	parameters := operationParameters(match.document, match.pointer, match.item, match.operation)
+	for _, key := range sortedParameterKeys(parameters) {
+		parameter := parameters[key].parameter
+		location, _ := parameter["in"].(string)
+		name, _ := parameter["name"].(string)
+		if location == "header" && ignoredHeaders[http.CanonicalHeaderKey(name)] {
+			continue
+		}
+		// The Swagger 2.0 body is checked as the request body, the form fields are not checked
+		if location == "body" || location == "formData" {
+			continue
+		}
+		values, present := parameterValues(req, match, location, name)
+		if !present {
+			if parameter["required"] == true || location == "path" {
+				violations = append(violations, &RequestViolation{In: location, Name: name, Message: "is required"})
+			}
+			continue
+		}
+		schema, ok := parameter["schema"]
+		if _, typed := parameter["type"]; !ok && typed {
+			// Swagger 2.0 parameters carry the schema keywords themselves
+			schema, ok = parameter, true
+		}
+		if !ok {
+			continue
+		}
+		value, err := coerceParameter(match.document, schema, values)
+		if err != nil {
+			violations = append(violations, &RequestViolation{In: location, Name: name, Message: err.Error()})
+			continue
+		}
+		schemaCheck := &schemaValidator{document: match.document, request: true}
+		schemaCheck.validate(schema, value, "", 0)
+		for _, schemaError := range schemaCheck.errors {
+			violations = append(violations, &RequestViolation{In: location, Name: name, Pointer: schemaError.pointer, Message: schemaError.message})
+		}
+	}
+	return append(violations, validator.validateBody(req, match)...)
+}
+
+// parameterValues returns the raw values of the parameter and whether it is present.
+func parameterValues(req *http.Request, match *operationMatch, location, name string) ([]string, bool) {
+	switch location {
+	case "path":
+		value, ok := match.params[name]
+		return []string{value}, ok
+	case "query":
+		values, ok := req.URL.Query()[name]
+		return values, ok
+	case "header":
+		values := req.Header.Values(name)
+		return values, len(values) > 0
+	case "cookie":
+		cookie, err := req.Cookie(name)
+		if err != nil {
+			return nil, false
+		}
+		return []string{cookie.Value}, true
+	}
+	return nil, false
+}
+
+// coerceParameter converts the raw values to the type of the schema.
+// Arrays are repeated values or comma separated, objects are not converted and not checked.
+func coerceParameter(document map[string]any, schemaValue any, values []string) (any, error) {
+	schema, _ := resolve(document, schemaValue)
+	schemaType, _ := schema["type"].(string)
+	switch schemaType {
+	case "array":
+		if len(values) == 1 {
+			values = strings.Split(values[0], ",")
+		}
+		items := make([]any, 0, len(values))
+		for _, value := range values {
+			item, err := coerceParameter(document, schema["items"], []string{value})
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case "object":
+		return nil, nil
+	}
+	value := values[0]
+	switch schemaType {
+	case "integer":
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("must be an integer")
+		}
+		return float64(number), nil
+	case "number":
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("must be a number")
+		}
+		return number, nil
+	case "boolean":
+		boolean, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("must be a boolean")
+		}
+		return boolean, nil
+	}
+	return value, nil
+}
+
+// validateBody checks the content type and the JSON body of the request.
+func (validator *requestValidator) validateBody(req *http.Request, match *operationMatch) []*RequestViolation {
+	requestBody, _ := resolve(match.document, match.operation["requestBody"])
+	if requestBody == nil {
+		requestBody = swaggerRequestBody(match)
+	}
+	if requestBody == nil {
+		return nil
+	}
+	body, complete := validator.readBody(req)
+	if len(body) == 0 && complete {
+		if requestBody["required"] == true {
+			return []*RequestViolation{{In: "body", Message: "is required"}}
+		}
+		return nil
+	}
+	content, _ := requestBody["content"].(map[string]any)
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	media, ok := content[mediaType].(map[string]any)
+	if !ok {
+		for _, key := range sortedKeys(content) {
+			// Ranges like application/*
+			if strings.HasSuffix(key, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(key, "*")) || key == "*/*" {
+				media, ok = content[key].(map[string]any)
+				break
+			}
+		}
+	}
+	if !ok {
+		return []*RequestViolation{{In: "body", Message: fmt.Sprintf("content type %q is not one of %v", mediaType, sortedKeys(content))}}
+	}
+	schema, ok := media["schema"]
+	if !ok || !isJSONMediaType(mediaType) || !complete {
+		return nil
+	}
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return []*RequestViolation{{In: "body", Message: fmt.Sprintf("is not valid JSON: %v", err)}}
+	}
+	schemaCheck := &schemaValidator{document: match.document, request: true}
+	schemaCheck.validate(schema, value, "", 0)
+	violations := make([]*RequestViolation, 0, len(schemaCheck.errors))
+	for _, schemaError := range schemaCheck.errors {
+		violations = append(violations, &RequestViolation{In: "body", Pointer: schemaError.pointer, Message: schemaError.message})
+	}
+	return violations
+}
+
+// swaggerContent describes the schema of a Swagger 2.0 body parameter or response as the content of an
+// OpenAPI 3.x request body or response, for every media type of consumes or produces, JSON by default.
+func swaggerContent(match *operationMatch, mediaTypesKey string, described map[string]any) map[string]any {
+	mediaTypes, ok := match.operation[mediaTypesKey].([]any)
+	if !ok {
+		mediaTypes, _ = match.document[mediaTypesKey].([]any)
+	}
+	if len(mediaTypes) == 0 {
+		mediaTypes = []any{"application/json"}
+	}
+	content := make(map[string]any, len(mediaTypes))
+	for _, mediaType := range mediaTypes {
+		media := make(map[string]any)
+		if schema, ok := described["schema"]; ok {
+			media["schema"] = schema
+		}
+		content[fmt.Sprint(mediaType)] = media
+	}
+	return content
+}
+
+// swaggerRequestBody describes the Swagger 2.0 body parameter of the operation as a request body
+// with the media types the operation consumes, JSON by default.
+func swaggerRequestBody(match *operationMatch) map[string]any {
+	parameters := operationParameters(match.document, match.pointer, match.item, match.operation)
+	for _, key := range sortedParameterKeys(parameters) {
+		parameter := parameters[key].parameter
+		if parameter["in"] != "body" {
+			continue
+		}
+		return map[string]any{"required": parameter["required"], "content": swaggerContent(match, "consumes", parameter)}
+	}
+	return nil
+}
+
+// readBody reads the body up to the size limit and restores it for the next handler.
+// It tells whether the whole body was read.
+func (validator *requestValidator) readBody(req *http.Request) ([]byte, bool) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, true
+	}
+	body, err := io.ReadAll(io.LimitReader(req.Body, validator.maxBodySize+1))
+	complete := err == nil && int64(len(body)) <= validator.maxBodySize
+	req.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), req.Body), req.Body}
+	return body, complete
+}
+
+// checkRequest validates the request and writes the problem when the request is rejected.
+// It returns false when the request must not be forwarded.
+func (swaggerMerger *SwaggerRing) checkRequest(rw http.ResponseWriter, req *http.Request, match *operationMatch) bool {
+	violations := swaggerMerger.requests.validateRequest(req, match)
+	if len(violations) == 0 {
+		return true
+	}
+	operation := match.method + " " + match.path
+	swaggerMerger.metrics.inc(swaggerMerger.metrics.requestViolations, match.source)
+	swaggerMerger.logger.Warn("request does not match the API description", "source", match.source,
+		"operation", operation, "violations", len(violations), "violation", violations[0].String())
+	if swaggerMerger.requests.report {
+		return true
+	}
+	problem, _ := json.Marshal(&RequestProblem{
+		Type:       "about:blank",
+		Title:      "Request does not match the API description",
+		Status:     http.StatusBadRequest,
+		Detail:     fmt.Sprintf("%d violations of %v", len(violations), operation),
+		Operation:  operation,
+		Violations: violations,
+	})
+	rw.Header().Set("Content-Type", "application/problem+json")
+	rw.WriteHeader(http.StatusBadRequest)
+	rw.Write(problem)
+	return false
+}
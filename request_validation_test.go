@@ -0,0 +1,288 @@
+package swagger_ring_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	swagger "github.com/usalko/swagger-ring"
+)
+
+const requestValidationSource = `
+openapi: 3.0.2
+info:
+  title: Orders
+  version: 1.0.0
+paths:
+  /orders/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        schema:
+          type: integer
+          minimum: 1
+    put:
+      parameters:
+        - name: X-Tenant
+          in: header
+          required: true
+          schema:
+            type: string
+        - name: tags
+          in: query
+          schema:
+            type: array
+            items:
+              type: string
+              enum: [new, paid]
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Order'
+      responses:
+        200:
+          description: OK
+components:
+  schemas:
+    Order:
+      type: object
+      required: [id, quantity]
+      additionalProperties: false
+      properties:
+        id:
+          type: integer
+          readOnly: true
+        quantity:
+          type: integer
+          minimum: 1
+        email:
+          type: string
+          format: email
+`
+
+func TestRequestValidation(t *testing.T) {
+	ctx := context.Background()
+
+	source := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(rw, requestValidationSource)
+	}))
+	defer source.Close()
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		fmt.Fprintf(rw, "next %s", body)
+	})
+
+	cfg := swagger.CreateConfig()
+	cfg.Path = "/api/v1/docs"
+	cfg.RequestValidation = &swagger.RequestValidationConfig{}
+	cfg.Docs = append(cfg.Docs, &swagger.DocPath{Path: source.URL + "/swagger.yaml"})
+
+	handler, err := swagger.New(ctx, next, cfg, "swagger-ring")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tt := []struct {
+		name        string
+		path        string
+		tenant      string
+		contentType string
+		body        string
+		violations  []string
+	}{
+		{name: "valid", path: "/orders/7?tags=new,paid", tenant: "acme", body: `{"quantity": 2, "email": "user@example.com"}`},
+		{name: "path parameter", path: "/orders/0", tenant: "acme", body: `{"quantity": 2}`, violations: []string{"path id: must be at least 1"}},
+		{name: "path parameter type", path: "/orders/first", tenant: "acme", body: `{"quantity": 2}`, violations: []string{"path id: must be an integer"}},
+		{name: "missing header", path: "/orders/7", body: `{"quantity": 2}`, violations: []string{"header X-Tenant: is required"}},
+		{name: "query enum", path: "/orders/7?tags=new&tags=lost", tenant: "acme", body: `{"quantity": 2}`, violations: []string{"query tags/1: must be one of [new paid]"}},
+		{name: "body", path: "/orders/7", tenant: "acme", body: `{"quantity": 0, "email": "user", "note": "x"}`, violations: []string{
+			"body/email: must be a valid email", "body/note: is not allowed", "body/quantity: must be at least 1",
+		}},
+		{name: "missing body", path: "/orders/7", tenant: "acme", violations: []string{"body: is required"}},
+		{name: "invalid JSON", path: "/orders/7", tenant: "acme", body: `{"quantity":`, violations: []string{"body: is not valid JSON: unexpected end of JSON input"}},
+		{name: "content type", path: "/orders/7", tenant: "acme", contentType: "text/plain", body: "2", violations: []string{`body: content type "text/plain" is not one of [application/json]`}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("PUT", tc.path, strings.NewReader(tc.body))
+			if tc.contentType == "" {
+				tc.contentType = "application/json"
+			}
+			req.Header.Set("Content-Type", tc.contentType)
+			if tc.tenant != "" {
+				req.Header.Set("X-Tenant", tc.tenant)
+			}
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+			if len(tc.violations) == 0 {
+				if expected := "next " + tc.body; rw.Body.String() != expected {
+					t.Errorf("expected response %s, got %s", expected, rw.Body.String())
+				}
+				return
+			}
+			if rw.Code != http.StatusBadRequest || rw.Header().Get("Content-Type") != "application/problem+json" {
+				t.Fatalf("expected problem+json 400, got %d %s", rw.Code, rw.Body.String())
+			}
+			problem := &swagger.RequestProblem{}
+			if err := json.Unmarshal(rw.Body.Bytes(), problem); err != nil {
+				t.Fatalf("expected JSON problem, got %v", err)
+			}
+			violations := make([]string, 0, len(problem.Violations))
+			for _, violation := range problem.Violations {
+				violations = append(violations, violation.String())
+			}
+			if strings.Join(violations, "\n") != strings.Join(tc.violations, "\n") {
+				t.Errorf("expected violations %q, got %q", tc.violations, violations)
+			}
+		})
+	}
+
+	cfg.RequestValidation.Mode = "report"
+	handler, err = swagger.New(ctx, next, cfg, "swagger-ring")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest("PUT", "/orders/0", strings.NewReader(`{}`)))
+	if rw.Body.String() != "next {}" {
+		t.Errorf("expected the invalid request to be forwarded in report mode, got %s", rw.Body.String())
+	}
+}
+
+const swaggerRequestValidationSource = `
+swagger: "2.0"
+info:
+  title: Auth
+  version: 1.0.0
+paths:
+  /auth/login:
+    post:
+      consumes:
+        - application/json
+      parameters:
+        - name: request
+          in: body
+          required: true
+          schema:
+            $ref: '#/definitions/LoginRequest'
+      responses:
+        200:
+          description: OK
+  /items/{id}:
+    post:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          type: integer
+        - name: limit
+          in: query
+          type: integer
+          maximum: 10
+        - name: sort
+          in: query
+          type: string
+          enum: [name, date]
+      responses:
+        200:
+          description: OK
+  /auth/avatar:
+    post:
+      consumes:
+        - multipart/form-data
+      parameters:
+        - name: file
+          in: formData
+          type: file
+          required: true
+      responses:
+        200:
+          description: OK
+definitions:
+  LoginRequest:
+    type: object
+    required: [login, password]
+    properties:
+      login:
+        type: string
+      password:
+        type: string
+        minLength: 8
+`
+
+func TestRequestValidationSwagger2(t *testing.T) {
+	ctx := context.Background()
+
+	source := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(rw, swaggerRequestValidationSource)
+	}))
+	defer source.Close()
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		fmt.Fprintf(rw, "next %s", body)
+	})
+
+	cfg := swagger.CreateConfig()
+	cfg.Path = "/api/v1/docs"
+	cfg.RequestValidation = &swagger.RequestValidationConfig{}
+	cfg.Docs = append(cfg.Docs, &swagger.DocPath{Path: source.URL + "/swagger.yaml"})
+
+	handler, err := swagger.New(ctx, next, cfg, "swagger-ring")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tt := []struct {
+		name        string
+		path        string
+		contentType string
+		body        string
+		violations  []string
+	}{
+		{name: "valid", path: "/auth/login", contentType: "application/json", body: `{"login": "admin", "password": "correct horse"}`},
+		{name: "body schema", path: "/auth/login", contentType: "application/json", body: `{"login": "admin", "password": "short"}`, violations: []string{"body/password: must be at least 8 characters long"}},
+		{name: "missing body", path: "/auth/login", contentType: "application/json", violations: []string{"body: is required"}},
+		{name: "content type", path: "/auth/login", contentType: "text/plain", body: "admin", violations: []string{`body: content type "text/plain" is not one of [application/json]`}},
+		{name: "valid parameters", path: "/items/7?limit=10&sort=name"},
+		{name: "path parameter type", path: "/items/abc", violations: []string{"path id: must be an integer"}},
+		{name: "query parameter type", path: "/items/7?limit=abc", violations: []string{"query limit: must be an integer"}},
+		{name: "query parameter maximum", path: "/items/7?limit=500", violations: []string{"query limit: must be at most 10"}},
+		{name: "query parameter enum", path: "/items/7?sort=size", violations: []string{"query sort: must be one of [name date]"}},
+		{name: "form data", path: "/auth/avatar", contentType: "multipart/form-data; boundary=x", body: "--x--"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", tc.contentType)
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+			if len(tc.violations) == 0 {
+				if expected := "next " + tc.body; rw.Body.String() != expected {
+					t.Errorf("expected response %s, got %s", expected, rw.Body.String())
+				}
+				return
+			}
+			problem := &swagger.RequestProblem{}
+			if err := json.Unmarshal(rw.Body.Bytes(), problem); err != nil {
+				t.Fatalf("expected JSON problem, got %v", err)
+			}
+			violations := make([]string, 0, len(problem.Violations))
+			for _, violation := range problem.Violations {
+				violations = append(violations, violation.String())
+			}
+			if strings.Join(violations, "\n") != strings.Join(tc.violations, "\n") {
+				t.Errorf("expected violations %q, got %q", tc.violations, violations)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package swagger_ring
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ResponseValidationConfig configures the shadow validation of the API responses against the merged document.
+// The responses are checked after they are written, the traffic is never changed.
+type ResponseValidationConfig struct {
+	// SampleRate is the share of the responses checked, from 0 to 1, 1 by default.
+	SampleRate float64 `json:"sampleRate"`
+	// MaxBodySize is the largest response body checked in bytes, 1 MiB by default.
+	MaxBodySize int64 `json:"maxBodySize"`
+}
+
+// responseValidator checks a sample of the API responses.
+type responseValidator struct {
+	sampleRate  float64
+	maxBodySize int64
+}
+
+func newResponseValidator(config *ResponseValidationConfig) (*responseValidator, error) {
+	if config == nil {
+		return nil, nil
+	}
+	if config.SampleRate < 0 || config.SampleRate > 1 {
+		return nil, fmt.Errorf("invalid sampleRate %v, it must be from 0 to 1", config.SampleRate)
+	}
+	validator := &responseValidator{sampleRate: config.SampleRate, maxBodySize: config.MaxBodySize}
+	if validator.sampleRate == 0 {
+		validator.sampleRate = 1
+	}
+	if validator.maxBodySize <= 0 {
+		validator.maxBodySize = defaultMaxBodySize
+	}
+	return validator, nil
+}
+
+// sampled tells whether the next response is checked.
+func (validator *responseValidator) sampled() bool {
+	return validator != nil && (validator.sampleRate >= 1 || rand.Float64() < validator.sampleRate)
+}
+
+// shadowWriter passes the response through and keeps a copy of its status and the beginning of its body.
+type shadowWriter struct {
+	http.ResponseWriter
+	status    int
+	body      bytes.Buffer
+	limit     int64
+	truncated bool
+}
+
+func (writer *shadowWriter) WriteHeader(status int) {
+	if writer.status == 0 {
+		writer.status = status
+	}
+	writer.ResponseWriter.WriteHeader(status)
+}
+
+func (writer *shadowWriter) Write(data []byte) (int, error) {
+	if writer.status == 0 {
+		writer.status = http.StatusOK
+	}
+	if room := writer.limit - int64(writer.body.Len()); int64(len(data)) > room {
+		writer.truncated = true
+		if room > 0 {
+			writer.body.Write(data[:room])
+		}
+	} else {
+		writer.body.Write(data)
+	}
+	return writer.ResponseWriter.Write(data)
+}
+
+// Flush supports streaming responses when the underlying writer does.
+func (writer *shadowWriter) Flush() {
+	if flusher, ok := writer.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap gives http.ResponseController access to the underlying writer.
+func (writer *shadowWriter) Unwrap() http.ResponseWriter {
+	return writer.ResponseWriter
+}
+
+// responseMismatch is a part of a response that does not match the operation.
+type responseMismatch struct {
+	// kind is status, content-type or body
+	kind    string
+	message string
+}
+
+// validateResponse returns the mismatches of the written response.
+func (validator *responseValidator) validateResponse(writer *shadowWriter, match *operationMatch) []*responseMismatch {
+	status := writer.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	responses, _ := match.operation["responses"].(map[string]any)
+	code := strconv.Itoa(status)
+	responseValue, ok := responses[code]
+	if !ok {
+		responseValue, ok = responses[code[:1]+"XX"]
+	}
+	if !ok {
+		responseValue, ok = responses["default"]
+	}
+	if !ok {
+		return []*responseMismatch{{kind: "status", message: fmt.Sprintf("status %d is not documented", status)}}
+	}
+	response, _ := resolve(match.document, responseValue)
+	content, _ := response["content"].(map[string]any)
+	if _, ok := response["schema"]; ok && content == nil {
+		// Swagger 2.0 responses have the schema directly
+		content = swaggerContent(match, "produces", response)
+	}
+	if len(content) == 0 {
+		if writer.body.Len() > 0 {
+			return []*responseMismatch{{kind: "body", message: fmt.Sprintf("status %d is documented without a body", status)}}
+		}
+		return nil
+	}
+	if writer.body.Len() == 0 && !writer.truncated {
+		return nil
+	}
+	mediaType, _, _ := mime.ParseMediaType(writer.Header().Get("Content-Type"))
+	media, ok := content[mediaType].(map[string]any)
+	if !ok {
+		for _, key := range sortedKeys(content) {
+			if key == "*/*" || strings.HasSuffix(key, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(key, "*")) {
+				media, ok = content[key].(map[string]any)
+				break
+			}
+		}
+	}
+	if !ok {
+		return []*responseMismatch{{kind: "content-type", message: fmt.Sprintf("content type %q is not one of %v", mediaType, sortedKeys(content))}}
+	}
+	schema, ok := media["schema"]
+	if !ok || !isJSONMediaType(mediaType) || writer.truncated {
+		return nil
+	}
+	// Compressed bodies are not checked
+	if encoding := writer.Header().Get("Content-Encoding"); encoding != "" && !strings.EqualFold(encoding, "identity") {
+		return nil
+	}
+	var value any
+	if err := json.Unmarshal(writer.body.Bytes(), &value); err != nil {
+		return []*responseMismatch{{kind: "body", message: fmt.Sprintf("is not valid JSON: %v", err)}}
+	}
+	schemaCheck := &schemaValidator{document: match.document}
+	schemaCheck.validate(schema, value, "", 0)
+	mismatches := make([]*responseMismatch, 0, len(schemaCheck.errors))
+	for _, schemaError := range schemaCheck.errors {
+		mismatches = append(mismatches, &responseMismatch{kind: "body", message: fmt.Sprintf("body%v: %v", schemaError.pointer, schemaError.message)})
+	}
+	return mismatches
+}
+
+// serveShadowed passes the request to the next handler and checks the response it writes.
+func (swaggerMerger *SwaggerRing) serveShadowed(rw http.ResponseWriter, req *http.Request, match *operationMatch) {
+	writer := &shadowWriter{ResponseWriter: rw, limit: swaggerMerger.responses.maxBodySize}
+	swaggerMerger.next.ServeHTTP(writer, req)
+
+	mismatches := swaggerMerger.responses.validateResponse(writer, match)
+	swaggerMerger.metrics.inc(swaggerMerger.metrics.responsesValidated, match.source)
+	if len(mismatches) == 0 {
+		return
+	}
+	for _, mismatch := range mismatches {
+		swaggerMerger.metrics.inc(swaggerMerger.metrics.responseMismatches, match.source, mismatch.kind)
+	}
+	swaggerMerger.logger.Warn("response does not match the API description", "source", match.source,
+		"operation", match.method+" "+match.path, "status", writer.status, "mismatches", len(mismatches), "mismatch", mismatches[0].message)
+}
@@ -0,0 +1,184 @@
+package swagger_ring_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	swagger "github.com/usalko/swagger-ring"
+)
+
+const responseValidationSource = `
+openapi: 3.0.2
+info:
+  title: Orders
+  version: 1.0.0
+paths:
+  /orders/{id}:
+    get:
+      responses:
+        200:
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id, status]
+                properties:
+                  id:
+                    type: integer
+                  status:
+                    type: string
+                    enum: [new, paid]
+        404:
+          description: Not found
+`
+
+func TestResponseValidation(t *testing.T) {
+	ctx := context.Background()
+
+	source := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(rw, responseValidationSource)
+	}))
+	defer source.Close()
+
+	var gzipped bytes.Buffer
+	compressor := gzip.NewWriter(&gzipped)
+	fmt.Fprint(compressor, `{"id": 6, "status": "new"}`)
+	compressor.Close()
+
+	responses := map[string]struct {
+		status      int
+		contentType string
+		encoding    string
+		body        string
+	}{
+		"/orders/1": {status: http.StatusOK, contentType: "application/json", body: `{"id": 1, "status": "paid"}`},
+		"/orders/2": {status: http.StatusOK, contentType: "application/json", body: `{"id": "2", "status": "lost"}`},
+		"/orders/3": {status: http.StatusOK, contentType: "text/html", body: "<p>3</p>"},
+		"/orders/4": {status: http.StatusInternalServerError, contentType: "text/plain", body: "oops"},
+		"/orders/5": {status: http.StatusNotFound},
+		"/orders/6": {status: http.StatusOK, contentType: "application/json", encoding: "gzip", body: gzipped.String()},
+	}
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		response := responses[req.URL.Path]
+		rw.Header().Set("Content-Type", response.contentType)
+		if response.encoding != "" {
+			rw.Header().Set("Content-Encoding", response.encoding)
+		}
+		rw.WriteHeader(response.status)
+		fmt.Fprint(rw, response.body)
+	})
+
+	cfg := swagger.CreateConfig()
+	cfg.Path = "/api/v1/docs"
+	cfg.MetricsPath = "/metrics"
+	cfg.ResponseValidation = &swagger.ResponseValidationConfig{SampleRate: 1}
+	cfg.Docs = append(cfg.Docs, &swagger.DocPath{Path: source.URL + "/swagger.yaml", Name: "orders"})
+
+	handler, err := swagger.New(ctx, next, cfg, "swagger-ring")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for path, response := range responses {
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, httptest.NewRequest("GET", path, nil))
+		if rw.Code != response.status || rw.Body.String() != response.body {
+			t.Errorf("expected the response of %v to pass unchanged, got %d %s", path, rw.Code, rw.Body.String())
+		}
+	}
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest("GET", "/api/v1/docs/metrics", nil))
+	for _, expected := range []string{
+		`swagger_ring_responses_validated_total{source="orders"} 6`,
+		`swagger_ring_response_mismatches_total{source="orders",kind="body"} 2`,
+		`swagger_ring_response_mismatches_total{source="orders",kind="content-type"} 1`,
+		`swagger_ring_response_mismatches_total{source="orders",kind="status"} 1`,
+	} {
+		if !strings.Contains(rw.Body.String(), expected) {
+			t.Errorf("expected metrics to contain %s, got %s", expected, rw.Body.String())
+		}
+	}
+
+	cfg.ResponseValidation.SampleRate = 2
+	if _, err := swagger.New(ctx, next, cfg, "swagger-ring"); err == nil {
+		t.Error("expected error for an invalid sample rate, got nil")
+	}
+}
+
+const swaggerResponseValidationSource = `
+swagger: "2.0"
+info:
+  title: Orders
+  version: 1.0.0
+produces:
+  - application/json
+paths:
+  /orders/{id}:
+    get:
+      responses:
+        200:
+          description: OK
+          schema:
+            $ref: '#/definitions/Order'
+        404:
+          description: Not found
+definitions:
+  Order:
+    type: object
+    required: [id]
+    properties:
+      id:
+        type: integer
+`
+
+func TestResponseValidationSwagger2(t *testing.T) {
+	ctx := context.Background()
+
+	source := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(rw, swaggerResponseValidationSource)
+	}))
+	defer source.Close()
+
+	bodies := map[string]string{"/orders/1": `{"id": 1}`, "/orders/2": `{"id": "2"}`, "/orders/3": "<p>3</p>"}
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/orders/3" {
+			rw.Header().Set("Content-Type", "text/html")
+		} else {
+			rw.Header().Set("Content-Type", "application/json")
+		}
+		fmt.Fprint(rw, bodies[req.URL.Path])
+	})
+
+	cfg := swagger.CreateConfig()
+	cfg.Path = "/api/v1/docs"
+	cfg.MetricsPath = "/metrics"
+	cfg.ResponseValidation = &swagger.ResponseValidationConfig{SampleRate: 1}
+	cfg.Docs = append(cfg.Docs, &swagger.DocPath{Path: source.URL + "/swagger.yaml", Name: "orders"})
+
+	handler, err := swagger.New(ctx, next, cfg, "swagger-ring")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for path := range bodies {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest("GET", "/api/v1/docs/metrics", nil))
+	for _, expected := range []string{
+		`swagger_ring_responses_validated_total{source="orders"} 3`,
+		`swagger_ring_response_mismatches_total{source="orders",kind="body"} 1`,
+		`swagger_ring_response_mismatches_total{source="orders",kind="content-type"} 1`,
+	} {
+		if !strings.Contains(rw.Body.String(), expected) {
+			t.Errorf("expected metrics to contain %s, got %s", expected, rw.Body.String())
+		}
+	}
+}
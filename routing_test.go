@@ -0,0 +1,114 @@
+package swagger_ring_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	swagger "github.com/usalko/swagger-ring"
+)
+
+func TestRouting(t *testing.T) {
+	ctx := context.Background()
+
+	source := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(rw, "openapi: 3.0.2\ninfo:\n  title: Orders\n  version: 1.0.0\npaths: {}\n")
+	}))
+	defer source.Close()
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(rw, "next")
+	})
+
+	cfg := swagger.CreateConfig()
+	cfg.Path = "/api/v1/docs"
+	cfg.PathRegex = `^/api/v[0-9]+/docs`
+	cfg.YAMLFile = "openapi.yaml"
+	cfg.JSONFile = "openapi.json"
+	cfg.Docs = append(cfg.Docs, &swagger.DocPath{Path: source.URL + "/swagger.yaml"})
+
+	handler, err := swagger.New(ctx, next, cfg, "swagger-ring")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tt := []struct {
+		name     string
+		path     string
+		contains string
+	}{
+		{name: "yaml document", path: "/api/v1/docs/openapi.yaml", contains: "title: Orders"},
+		{name: "json document", path: "/api/v1/docs/openapi.json", contains: `"title":"Orders"`},
+		{name: "page", path: "/api/v1/docs", contains: `"/api/v1/docs/openapi.yaml"`},
+		{name: "default file name", path: "/api/v1/docs/swagger.yaml", contains: "next"},
+		{name: "api call", path: "/api/v1/feature1/export.json", contains: "next"},
+		{name: "path regex document", path: "/api/v2/docs/openapi.json", contains: `"title":"Orders"`},
+		{name: "path regex page", path: "/api/v2/docs", contains: `"/api/v2/docs/openapi.yaml"`},
+		{name: "path regex prefix", path: "/api/v2/docsets/openapi.json", contains: "next"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, httptest.NewRequest("GET", tc.path, nil))
+			if !strings.Contains(rw.Body.String(), tc.contains) {
+				t.Errorf("expected response to contain %s, got %s", tc.contains, rw.Body.String())
+			}
+		})
+	}
+
+	cfg.JSONFile = "openapi.yaml"
+	if _, err := swagger.New(ctx, next, cfg, "swagger-ring"); err == nil {
+		t.Error("expected error for the same document file names, got nil")
+	}
+}
+
+func TestRoutingTrailingSlash(t *testing.T) {
+	ctx := context.Background()
+
+	source := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(rw, "openapi: 3.0.2\ninfo:\n  title: Orders\n  version: 1.0.0\npaths: {}\n")
+	}))
+	defer source.Close()
+
+	next := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(rw, "next")
+	})
+
+	tt := []struct {
+		name     string
+		config   string
+		path     string
+		contains string
+		excludes string
+	}{
+		{name: "trailing slash page", config: "/api/v1/docs/", path: "/api/v1/docs", contains: `"/api/v1/docs/swagger.yaml"`, excludes: "//swagger.yaml"},
+		{name: "trailing slash document", config: "/api/v1/docs/", path: "/api/v1/docs/swagger.yaml", contains: "title: Orders"},
+		{name: "trailing slash api call", config: "/api/v1/docs/", path: "/api/v1/orders", contains: "next"},
+		{name: "root page", config: "/", path: "/", contains: `"/swagger.yaml"`, excludes: "//swagger.yaml"},
+		{name: "root document", config: "/", path: "/swagger.json", contains: `"title":"Orders"`},
+		{name: "root status", config: "/", path: "/_status", contains: `"sources"`},
+		{name: "root api call", config: "/", path: "/api/v1/orders", contains: "next"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := swagger.CreateConfig()
+			cfg.Path = tc.config
+			cfg.Docs = append(cfg.Docs, &swagger.DocPath{Path: source.URL + "/swagger.yaml"})
+			handler, err := swagger.New(ctx, next, cfg, "swagger-ring")
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, httptest.NewRequest("GET", tc.path, nil))
+			if !strings.Contains(rw.Body.String(), tc.contains) {
+				t.Errorf("expected response to contain %s, got %s", tc.contains, rw.Body.String())
+			}
+			if tc.excludes != "" && strings.Contains(rw.Body.String(), tc.excludes) {
+				t.Errorf("expected response not to contain %s, got %s", tc.excludes, rw.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,315 @@
+package swagger_ring
+
+import (
+	"fmt"
+	"math"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// maxSchemaDepth limits how deep a value is validated against a recursive schema.
+const maxSchemaDepth = 32
+
+// schemaError is a value that does not satisfy its schema.
+type schemaError struct {
+	pointer string
+	message string
+}
+
+// schemaValidator checks JSON values against the OpenAPI schemas of a document.
+// Read-only properties are not required in requests and write-only properties are not required in responses.
+type schemaValidator struct {
+	document map[string]any
+	request  bool
+	errors   []*schemaError
+}
+
+var schemaPatterns sync.Map
+
+func (validator *schemaValidator) fail(pointer string, format string, args ...any) {
+	validator.errors = append(validator.errors, &schemaError{pointer: pointer, message: fmt.Sprintf(format, args...)})
+}
+
+// matches tells whether the value satisfies the schema without recording the errors.
+func (validator *schemaValidator) matches(schemaValue, value any, pointer string, depth int) bool {
+	child := &schemaValidator{document: validator.document, request: validator.request}
+	child.validate(schemaValue, value, pointer, depth)
+	return len(child.errors) == 0
+}
+
+// validate records the errors of the value at the pointer against the schema.
+func (validator *schemaValidator) validate(schemaValue, value any, pointer string, depth int) {
+	schema, _ := resolve(validator.document, schemaValue)
+	if schema == nil || depth > maxSchemaDepth {
+		return
+	}
+	if value == nil && (schema["nullable"] == true || schemaAllowsType(schema, "null")) {
+		return
+	}
+
+	for _, part := range asList(schema["allOf"]) {
+		validator.validate(part, value, pointer, depth+1)
+	}
+	if anyOf := asList(schema["anyOf"]); len(anyOf) > 0 {
+		matched := false
+		for _, part := range anyOf {
+			matched = matched || validator.matches(part, value, pointer, depth+1)
+		}
+		if !matched {
+			validator.fail(pointer, "does not match any of the anyOf schemas")
+		}
+	}
+	if oneOf := asList(schema["oneOf"]); len(oneOf) > 0 {
+		matched := 0
+		for _, part := range oneOf {
+			if validator.matches(part, value, pointer, depth+1) {
+				matched++
+			}
+		}
+		if matched != 1 {
+			validator.fail(pointer, "matches %d of the oneOf schemas instead of one", matched)
+		}
+	}
+	if not, ok := schema["not"]; ok && validator.matches(not, value, pointer, depth+1) {
+		validator.fail(pointer, "matches the not schema")
+	}
+	if values, ok := schema["enum"].([]any); ok {
+		found := false
+		for _, allowed := range values {
+			found = found || sameValue(allowed, value)
+		}
+		if !found {
+			validator.fail(pointer, "must be one of %v", values)
+		}
+	}
+
+	if !validator.validateType(schema, value, pointer) {
+		return
+	}
+	switch typed := value.(type) {
+	case string:
+		validator.validateString(schema, typed, pointer)
+	case []any:
+		validator.validateArray(schema, typed, pointer, depth)
+	case map[string]any:
+		validator.validateObject(schema, typed, pointer, depth)
+	default:
+		if number, ok := toFloat(value); ok {
+			validator.validateNumber(schema, number, pointer)
+		}
+	}
+}
+
+// validateType checks the type of the value and tells whether the other keywords apply.
+func (validator *schemaValidator) validateType(schema map[string]any, value any, pointer string) bool {
+	types := asList(schema["type"])
+	if schemaType, ok := schema["type"].(string); ok {
+		types = []any{schemaType}
+	}
+	if len(types) == 0 {
+		return true
+	}
+	for _, schemaType := range types {
+		if name, _ := schemaType.(string); valueHasType(value, name) {
+			return true
+		}
+	}
+	validator.fail(pointer, "must be of type %v", schema["type"])
+	return false
+}
+
+func (validator *schemaValidator) validateString(schema map[string]any, value, pointer string) {
+	length := utf8.RuneCountInString(value)
+	if minLength, ok := toFloat(schema["minLength"]); ok && float64(length) < minLength {
+		validator.fail(pointer, "must be at least %v characters long", minLength)
+	}
+	if maxLength, ok := toFloat(schema["maxLength"]); ok && float64(length) > maxLength {
+		validator.fail(pointer, "must be at most %v characters long", maxLength)
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		if expression := compileSchemaPattern(pattern); expression != nil && !expression.MatchString(value) {
+			validator.fail(pointer, "must match the pattern %v", pattern)
+		}
+	}
+	format, _ := schema["format"].(string)
+	if check, ok := stringFormats[format]; ok && !check(value) {
+		validator.fail(pointer, "must be a valid %v", format)
+	}
+}
+
+var stringFormats = map[string]func(string) bool{
+	"date-time": func(value string) bool {
+		_, err := time.Parse(time.RFC3339, value)
+		return err == nil
+	},
+	"date": func(value string) bool {
+		_, err := time.Parse(time.DateOnly, value)
+		return err == nil
+	},
+	"email": func(value string) bool {
+		address, err := mail.ParseAddress(value)
+		return err == nil && address.Address == value
+	},
+	"uuid": regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`).MatchString,
+}
+
+func compileSchemaPattern(pattern string) *regexp.Regexp {
+	if expression, ok := schemaPatterns.Load(pattern); ok {
+		return expression.(*regexp.Regexp)
+	}
+	expression, err := regexp.Compile(pattern)
+	if err != nil {
+		// Patterns Go cannot compile are not checked
+		return nil
+	}
+	schemaPatterns.Store(pattern, expression)
+	return expression
+}
+
+func (validator *schemaValidator) validateNumber(schema map[string]any, value float64, pointer string) {
+	if minimum, ok := toFloat(schema["minimum"]); ok {
+		if schema["exclusiveMinimum"] == true && value <= minimum {
+			validator.fail(pointer, "must be greater than %v", minimum)
+		} else if value < minimum {
+			validator.fail(pointer, "must be at least %v", minimum)
+		}
+	}
+	if maximum, ok := toFloat(schema["maximum"]); ok {
+		if schema["exclusiveMaximum"] == true && value >= maximum {
+			validator.fail(pointer, "must be less than %v", maximum)
+		} else if value > maximum {
+			validator.fail(pointer, "must be at most %v", maximum)
+		}
+	}
+	// OpenAPI 3.1 exclusive bounds are numbers
+	if minimum, ok := toFloat(schema["exclusiveMinimum"]); ok && value <= minimum {
+		validator.fail(pointer, "must be greater than %v", minimum)
+	}
+	if maximum, ok := toFloat(schema["exclusiveMaximum"]); ok && value >= maximum {
+		validator.fail(pointer, "must be less than %v", maximum)
+	}
+	if multipleOf, ok := toFloat(schema["multipleOf"]); ok && multipleOf > 0 {
+		if quotient := value / multipleOf; math.Abs(quotient-math.Round(quotient)) > 1e-9 {
+			validator.fail(pointer, "must be a multiple of %v", multipleOf)
+		}
+	}
+}
+
+func (validator *schemaValidator) validateArray(schema map[string]any, value []any, pointer string, depth int) {
+	if minItems, ok := toFloat(schema["minItems"]); ok && float64(len(value)) < minItems {
+		validator.fail(pointer, "must have at least %v items", minItems)
+	}
+	if maxItems, ok := toFloat(schema["maxItems"]); ok && float64(len(value)) > maxItems {
+		validator.fail(pointer, "must have at most %v items", maxItems)
+	}
+	if schema["uniqueItems"] == true {
+		for i := range value {
+			for j := i + 1; j < len(value); j++ {
+				if sameValue(value[i], value[j]) {
+					validator.fail(pointer, "must have unique items, %d and %d are equal", i, j)
+				}
+			}
+		}
+	}
+	if items, ok := schema["items"]; ok {
+		for i, item := range value {
+			validator.validate(items, item, joinPointer(pointer, i), depth+1)
+		}
+	}
+}
+
+func (validator *schemaValidator) validateObject(schema map[string]any, value map[string]any, pointer string, depth int) {
+	properties, _ := schema["properties"].(map[string]any)
+	for _, required := range asList(schema["required"]) {
+		name, _ := required.(string)
+		if _, ok := value[name]; ok {
+			continue
+		}
+		property, _ := resolve(validator.document, properties[name])
+		if validator.request && property["readOnly"] == true || !validator.request && property["writeOnly"] == true {
+			continue
+		}
+		validator.fail(pointer, "must have the property %v", name)
+	}
+	if minProperties, ok := toFloat(schema["minProperties"]); ok && float64(len(value)) < minProperties {
+		validator.fail(pointer, "must have at least %v properties", minProperties)
+	}
+	if maxProperties, ok := toFloat(schema["maxProperties"]); ok && float64(len(value)) > maxProperties {
+		validator.fail(pointer, "must have at most %v properties", maxProperties)
+	}
+	keys := make([]string, 0, len(value))
+	for key := range value {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if property, ok := properties[key]; ok {
+			validator.validate(property, value[key], joinPointer(pointer, key), depth+1)
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				validator.fail(joinPointer(pointer, key), "is not allowed")
+			}
+		case map[string]any:
+			validator.validate(additional, value[key], joinPointer(pointer, key), depth+1)
+		}
+	}
+}
+
+func asList(value any) []any {
+	list, _ := value.([]any)
+	return list
+}
+
+func schemaAllowsType(schema map[string]any, name string) bool {
+	for _, schemaType := range asList(schema["type"]) {
+		if schemaType == name {
+			return true
+		}
+	}
+	return false
+}
+
+// valueHasType tells whether the decoded JSON value has the JSON schema type.
+func valueHasType(value any, name string) bool {
+	switch name {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	case "number":
+		_, ok := toFloat(value)
+		return ok
+	case "integer":
+		number, ok := toFloat(value)
+		return ok && number == math.Trunc(number)
+	}
+	return false
+}
+
+// sameValue compares decoded values, numbers by their value whatever their Go type.
+func sameValue(left, right any) bool {
+	leftNumber, leftOk := toFloat(left)
+	rightNumber, rightOk := toFloat(right)
+	if leftOk && rightOk {
+		return leftNumber == rightNumber
+	}
+	return reflect.DeepEqual(left, right)
+}
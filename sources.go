@@ -0,0 +1,96 @@
+package swagger_ring
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	sourceNamePattern = regexp.MustCompile(`^[a-zA-Z0-9._-]+$`)
+	sourceNameCleaner = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+)
+
+// assignSourceNames gives every document a unique name, the configured one
+// or the host of the document URL.
+func assignSourceNames(refs []DocPath) error {
+	used := make(map[string]bool, len(refs))
+	for i := range refs {
+		name := refs[i].Name
+		if name == "" {
+			continue
+		}
+		if !sourceNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid name %q of %v, it must match %v", name, refs[i].Path, sourceNamePattern)
+		}
+		if used[name] {
+			return fmt.Errorf("duplicated name %q", name)
+		}
+		used[name] = true
+	}
+	for i := range refs {
+		if refs[i].Name != "" {
+			continue
+		}
+		base := defaultSourceName(refs[i].Path)
+		name := base
+		for n := 2; used[name]; n++ {
+			name = fmt.Sprintf("%v-%d", base, n)
+		}
+		refs[i].Name = name
+		used[name] = true
+	}
+	return nil
+}
+
+func defaultSourceName(path string) string {
+	name := ""
+	if parsed, err := url.Parse(path); err == nil {
+		name = parsed.Hostname()
+	}
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	name = strings.Trim(sourceNameCleaner.ReplaceAllString(name, "-"), "-")
+	if name == "" {
+		return "source"
+	}
+	return name
+}
+
+// GetSourceDoc returns the source document by its name rendered as YAML or JSON,
+// fetched and cached the same way as for the merged document.
+func (swaggerMerger *SwaggerRing) GetSourceDoc(name string, docType DocType) (string, error) {
+	for i := range swaggerMerger.refs {
+		ref := &swaggerMerger.refs[i]
+		if ref.Name != name {
+			continue
+		}
+		return swaggerMerger.cache.get(fmt.Sprintf("source.%v.%d", name, docType), func() (string, error) {
+			swagger, err := swaggerMerger.fetchDocument(ref)
+			if err != nil {
+				return "", err
+			}
+			return swaggerMerger.renderDocument(swagger, docType)
+		})
+	}
+	return "", nil
+}
+
+// serveSource writes the source document addressed by `{path}/sources/{name}.yaml|.json`.
+func (swaggerMerger *SwaggerRing) serveSource(rw http.ResponseWriter, file string) {
+	name, docType := parseDocumentName(file)
+	source, err := swaggerMerger.GetSourceDoc(name, docType)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadGateway)
+		return
+	}
+	if source == "" {
+		http.Error(rw, fmt.Sprintf("source %v not found", name), http.StatusNotFound)
+		return
+	}
+	writeDocument(rw, source, docType)
+}
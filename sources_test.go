@@ -0,0 +1,65 @@
+package swagger_ring_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	swagger "github.com/usalko/swagger-ring"
+)
+
+func TestSources(t *testing.T) {
+	ctx := context.Background()
+
+	source := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(rw, "openapi: 3.0.2\ninfo:\n  title: %v\n  version: 1.0.0\npaths: {}\n", strings.TrimSuffix(req.URL.Path[1:], ".yaml"))
+	}))
+	defer source.Close()
+
+	cfg := swagger.CreateConfig()
+	cfg.Path = "/api/v1/docs"
+	cfg.UI = &swagger.UIConfig{Renderer: "swagger-ui", SourcePicker: true}
+	cfg.Docs = append(cfg.Docs,
+		&swagger.DocPath{Path: source.URL + "/users.yaml"},
+		&swagger.DocPath{Path: source.URL + "/orders.yaml", Name: "orders"},
+	)
+
+	handler, err := swagger.New(ctx, http.NotFoundHandler(), cfg, "swagger-ring")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tt := []struct {
+		name     string
+		path     string
+		status   int
+		contains string
+	}{
+		{name: "page", path: "/api/v1/docs", status: http.StatusOK, contains: `"urls":[{"name":"API Documentation","title":"API Documentation","url":"/api/v1/docs/swagger.yaml"},{"name":"127.0.0.1","title":"127.0.0.1","url":"/api/v1/docs/sources/127.0.0.1.yaml"},{"name":"orders","title":"orders","url":"/api/v1/docs/sources/orders.yaml"}]`},
+		{name: "standalone layout", path: "/api/v1/docs", status: http.StatusOK, contains: "layout: 'StandaloneLayout'"},
+		{name: "standalone preset", path: "/api/v1/docs", status: http.StatusOK, contains: `src="https://cdn.jsdelivr.net/npm/swagger-ui-dist/swagger-ui-standalone-preset.js"`},
+		{name: "default name", path: "/api/v1/docs/sources/127.0.0.1.yaml", status: http.StatusOK, contains: "title: users"},
+		{name: "configured name as json", path: "/api/v1/docs/sources/orders.json", status: http.StatusOK, contains: `"title":"orders"`},
+		{name: "unknown", path: "/api/v1/docs/sources/billing.yaml", status: http.StatusNotFound, contains: "source billing not found"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, httptest.NewRequest("GET", tc.path, nil))
+			if rw.Code != tc.status {
+				t.Errorf("expected status %d, got %d", tc.status, rw.Code)
+			}
+			if !strings.Contains(rw.Body.String(), tc.contains) {
+				t.Errorf("expected response to contain %s, got %s", tc.contains, rw.Body.String())
+			}
+		})
+	}
+
+	cfg.Docs[0].Name = "orders"
+	if _, err := swagger.New(ctx, http.NotFoundHandler(), cfg, "swagger-ring"); err == nil {
+		t.Error("expected error for duplicated source names, got nil")
+	}
+}
@@ -0,0 +1,161 @@
+package swagger_ring
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// templateData is the data of a content template, e.g. `{{.Query.Get "name"}}` or `{{.Params.version}}`.
+type templateData struct {
+	Method  string
+	Path    string
+	Query   url.Values
+	Headers http.Header
+	Host    string
+	// Params are the named capture groups of the path regex.
+	Params map[string]string
+}
+
+// templateFuncs are the helper functions available in content templates.
+var templateFuncs = template.FuncMap{
+	"json": func(value any) (string, error) {
+		data, err := json.Marshal(value)
+		return string(data), err
+	},
+	"toYaml": func(value any) (string, error) {
+		data, err := yaml.Marshal(value)
+		return string(data), err
+	},
+	// default returns the fallback when the value is empty: `{{.Query.Get "name" | default "world"}}`
+	"default": func(fallback, value any) any {
+		if value == nil || reflect.ValueOf(value).IsZero() {
+			return fallback
+		}
+		if reflected := reflect.ValueOf(value); (reflected.Kind() == reflect.Map || reflected.Kind() == reflect.Slice) && reflected.Len() == 0 {
+			return fallback
+		}
+		return value
+	},
+	"env": os.Getenv,
+	"now": time.Now,
+	"base64": func(value any) string {
+		return base64.StdEncoding.EncodeToString([]byte(fmt.Sprint(value)))
+	},
+}
+
+// isStatic tells whether the DocPath answers requests with a configured response
+// instead of referencing a document to merge.
+func (ref *DocPath) isStatic() bool {
+	return ref.Content != "" || ref.JSONData != nil || ref.PathRegex != "" || ref.Status != 0
+}
+
+// compile prepares the static response: the path regex, the content template and the JSON body.
+func (ref *DocPath) compile() error {
+	if ref.Path == "" && ref.PathRegex == "" {
+		return fmt.Errorf("path or pathRegex is required")
+	}
+	if ref.Content != "" && ref.JSONData != nil {
+		return fmt.Errorf("content and jsonData cannot be used together")
+	}
+	if len(ref.Overlays) > 0 {
+		return fmt.Errorf("overlays cannot be applied to a static response")
+	}
+	if ref.Status != 0 && (ref.Status < 100 || ref.Status > 999) {
+		return fmt.Errorf("invalid status %d", ref.Status)
+	}
+	if ref.Indent < 0 {
+		return fmt.Errorf("invalid indent %d", ref.Indent)
+	}
+	if ref.PathRegex != "" {
+		pathRegex, err := regexp.Compile(ref.PathRegex)
+		if err != nil {
+			return fmt.Errorf("invalid pathRegex %v: %w", ref.PathRegex, err)
+		}
+		ref.pathRegex = pathRegex
+	}
+	if ref.Content != "" {
+		contentTemplate, err := template.New("content").Funcs(templateFuncs).Parse(ref.Content)
+		if err != nil {
+			return fmt.Errorf("invalid content template: %w", err)
+		}
+		ref.template = contentTemplate
+	}
+	if ref.JSONData != nil {
+		data := normalizeDocument(ref.JSONData)
+		var err error
+		if ref.Indent > 0 {
+			ref.jsonData, err = json.MarshalIndent(data, "", strings.Repeat(" ", ref.Indent))
+		} else {
+			ref.jsonData, err = json.Marshal(data)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid jsonData: %w", err)
+		}
+	}
+	return nil
+}
+
+// matches tells whether the static response answers the request path,
+// the exact path is checked before the path regex.
+func (ref *DocPath) matches(path string) bool {
+	if ref.Path != "" && ref.Path == path {
+		return true
+	}
+	return ref.pathRegex != nil && ref.pathRegex.MatchString(path)
+}
+
+// templateData returns the request data of the content template.
+func (ref *DocPath) templateData(req *http.Request) *templateData {
+	data := &templateData{
+		Method:  req.Method,
+		Path:    req.URL.Path,
+		Query:   req.URL.Query(),
+		Headers: req.Header,
+		Host:    req.Host,
+		Params:  make(map[string]string),
+	}
+	if ref.pathRegex != nil {
+		if match := ref.pathRegex.FindStringSubmatch(req.URL.Path); match != nil {
+			for i, name := range ref.pathRegex.SubexpNames() {
+				if name != "" {
+					data.Params[name] = match[i]
+				}
+			}
+		}
+	}
+	return data
+}
+
+// serveStatic writes the configured response with the configured status, 200 by default.
+func (swaggerMerger *SwaggerRing) serveStatic(rw http.ResponseWriter, req *http.Request, ref *DocPath) {
+	status := ref.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	body := ref.jsonData
+	if ref.jsonData != nil {
+		rw.Header().Set("Content-Type", "application/json")
+	}
+	if ref.template != nil {
+		buf := bytes.NewBufferString("")
+		if err := ref.template.Execute(buf, ref.templateData(req)); err != nil {
+			http.Error(rw, fmt.Sprintf("content template issue: %v", err), http.StatusInternalServerError)
+			return
+		}
+		body = buf.Bytes()
+	}
+	rw.WriteHeader(status)
+	rw.Write(body)
+}
@@ -0,0 +1,98 @@
+package swagger_ring_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	swagger "github.com/usalko/swagger-ring"
+)
+
+func TestStaticJSONResponse(t *testing.T) {
+	ctx := context.Background()
+
+	cfg := swagger.CreateConfig()
+	cfg.Docs = append(cfg.Docs, &swagger.DocPath{
+		PathRegex: "^/health$",
+		JSONData:  map[string]any{"status": "down"},
+		Indent:    2,
+		Status:    http.StatusServiceUnavailable,
+	})
+
+	handler, err := swagger.New(ctx, http.NotFoundHandler(), cfg, "swagger-ring")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest("GET", "/health", nil))
+	if rw.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status %d, got %d", http.StatusServiceUnavailable, rw.Code)
+	}
+	if contentType := rw.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("expected application/json, got %v", contentType)
+	}
+	if expected := "{\n  \"status\": \"down\"\n}"; rw.Body.String() != expected {
+		t.Errorf("expected response %s, got %s", expected, rw.Body.String())
+	}
+}
+
+func TestStaticResponseErrors(t *testing.T) {
+	ctx := context.Background()
+
+	tt := []struct {
+		name string
+		doc  *swagger.DocPath
+	}{
+		{name: "regex", doc: &swagger.DocPath{PathRegex: "^/(", Content: "Hello"}},
+		{name: "template", doc: &swagger.DocPath{Path: "/", Content: "Hello {{.Name"}},
+		{name: "content and json", doc: &swagger.DocPath{Path: "/", Content: "Hello", JSONData: map[string]any{}}},
+		{name: "status", doc: &swagger.DocPath{Path: "/", Status: 42}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := swagger.CreateConfig()
+			cfg.Docs = append(cfg.Docs, tc.doc)
+			if _, err := swagger.New(ctx, http.NotFoundHandler(), cfg, "swagger-ring"); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestStaticTemplate(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("SWAGGER_RING_RELEASE", "1.2.3")
+
+	cfg := swagger.CreateConfig()
+	cfg.Docs = append(cfg.Docs, &swagger.DocPath{
+		PathRegex: `^/greet/(?P<version>v[0-9]+)$`,
+		Content:   `{{.Method}} {{.Host}}{{.Path}} {{.Params.version}}: Hello {{.Query.Get "name" | default "world"}} from {{.Headers.Get "X-Team"}}, {{env "SWAGGER_RING_RELEASE"}} {{base64 "ring"}} {{json .Params}}{{if now.IsZero}} zero{{end}}`,
+	})
+
+	handler, err := swagger.New(ctx, http.NotFoundHandler(), cfg, "swagger-ring")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	tt := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{name: "query", path: "/greet/v2?name=ring", expected: `GET example.com/greet/v2 v2: Hello ring from docs, 1.2.3 cmluZw== {"version":"v2"}`},
+		{name: "default", path: "/greet/v1", expected: `GET example.com/greet/v1 v1: Hello world from docs, 1.2.3 cmluZw== {"version":"v1"}`},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tc.path, nil)
+			req.Header.Set("X-Team", "docs")
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, req)
+			if rw.Body.String() != tc.expected {
+				t.Errorf("expected response %s, got %s", tc.expected, rw.Body.String())
+			}
+		})
+	}
+}
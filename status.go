@@ -0,0 +1,109 @@
+package swagger_ring
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// SourceStatus describes the last fetch of a source document.
+type SourceStatus struct {
+	Name      string    `json:"name"`
+	URL       string    `json:"url"`
+	FetchedAt time.Time `json:"fetchedAt"`
+	// Status is the HTTP status of the response, zero when the request failed.
+	Status    int     `json:"status"`
+	LatencyMS float64 `json:"latencyMs"`
+	Size      int     `json:"size"`
+	// Format is yaml or json, detected by the extension of the URL.
+	Format         string `json:"format,omitempty"`
+	OpenAPIVersion string `json:"openapiVersion,omitempty"`
+	Error          string `json:"error,omitempty"`
+	// Operations and Components are the numbers of operations and components the document contributes.
+	Operations int `json:"operations"`
+	Components int `json:"components"`
+	// Stale tells that the fetch failed and the last good copy is merged instead.
+	Stale       bool       `json:"stale"`
+	LastSuccess *time.Time `json:"lastSuccess,omitempty"`
+}
+
+// StatusReport lists the status of every source document.
+type StatusReport struct {
+	Sources []*SourceStatus `json:"sources"`
+}
+
+// sourceState keeps the status and the last good copy of a source document.
+type sourceState struct {
+	mutex    sync.Mutex
+	status   *SourceStatus
+	document map[string]any
+}
+
+// update records the fetch. When the fetch failed it returns the last good copy, if there is one, and true.
+func (state *sourceState) update(fetched *SourceStatus, document map[string]any, err error) (map[string]any, bool) {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+	if err == nil {
+		fetched.OpenAPIVersion, fetched.Operations, fetched.Components = documentSummary(document)
+		fetched.LastSuccess = &fetched.FetchedAt
+		state.status, state.document = fetched, deepCopy(document).(map[string]any)
+		return document, false
+	}
+	fetched.Error = err.Error()
+	if state.document == nil {
+		state.status = fetched
+		return nil, false
+	}
+	fetched.Stale = true
+	fetched.OpenAPIVersion, fetched.Operations, fetched.Components = documentSummary(state.document)
+	fetched.LastSuccess = state.status.LastSuccess
+	state.status = fetched
+	return deepCopy(state.document).(map[string]any), true
+}
+
+// documentSummary returns the OpenAPI version and the numbers of operations and components of the document.
+func documentSummary(document map[string]any) (string, int, int) {
+	version, _ := document["openapi"].(string)
+	if swagger, ok := document["swagger"].(string); ok && version == "" {
+		version = swagger
+	}
+	operations := 0
+	paths, _ := document["paths"].(map[string]any)
+	for _, itemValue := range paths {
+		item, _ := itemValue.(map[string]any)
+		for _, method := range httpMethods {
+			if _, ok := item[method].(map[string]any); ok {
+				operations++
+			}
+		}
+	}
+	components := 0
+	groups, _ := document["components"].(map[string]any)
+	for _, groupValue := range groups {
+		group, _ := groupValue.(map[string]any)
+		components += len(group)
+	}
+	return version, operations, components
+}
+
+// GetStatusReport returns the status of every source document as JSON.
+func (swaggerMerger *SwaggerRing) GetStatusReport() (string, error) {
+	// Refresh the sources, the report is useful even when the merged document cannot be served
+	swaggerMerger.GetMergedSwaggerDoc(DOC_TYPE_JSON)
+	report := &StatusReport{Sources: make([]*SourceStatus, 0, len(swaggerMerger.refs))}
+	for i := range swaggerMerger.refs {
+		ref := &swaggerMerger.refs[i]
+		ref.state.mutex.Lock()
+		status := ref.state.status
+		ref.state.mutex.Unlock()
+		if status == nil {
+			status = &SourceStatus{Name: ref.Name, URL: redactURL(ref.Path)}
+		}
+		report.Sources = append(report.Sources, status)
+	}
+	data, err := json.Marshal(report)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
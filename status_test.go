@@ -0,0 +1,68 @@
+package swagger_ring_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	swagger "github.com/usalko/swagger-ring"
+)
+
+func TestStatus(t *testing.T) {
+	ctx := context.Background()
+
+	var down atomic.Bool
+	source := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/missing.json" || down.Load() {
+			http.Error(rw, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(rw, "openapi: 3.0.2\ninfo:\n  title: Orders\n  version: 1.0.0\npaths:\n  /orders:\n    get:\n      responses: {}\n    post:\n      responses: {}\ncomponents:\n  schemas:\n    Order:\n      type: object\n")
+	}))
+	defer source.Close()
+
+	cfg := swagger.CreateConfig()
+	cfg.Path = "/api/v1/docs"
+	cfg.Docs = append(cfg.Docs,
+		&swagger.DocPath{Path: source.URL + "/swagger.yaml", Name: "orders"},
+		&swagger.DocPath{Path: source.URL + "/missing.json", Name: "missing"},
+	)
+
+	handler, err := swagger.New(ctx, http.NotFoundHandler(), cfg, "swagger-ring")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	report := getJSON[*swagger.StatusReport](t, handler, "/api/v1/docs/_status")
+	if len(report.Sources) != 2 {
+		t.Fatalf("expected 2 sources, got %+v", report.Sources)
+	}
+	orders, missing := report.Sources[0], report.Sources[1]
+	if orders.Status != http.StatusOK || orders.Format != "yaml" || orders.OpenAPIVersion != "3.0.2" || orders.Size == 0 {
+		t.Errorf("expected a successful yaml fetch, got %+v", orders)
+	}
+	if orders.Operations != 2 || orders.Components != 1 || orders.Stale || orders.LastSuccess == nil {
+		t.Errorf("expected 2 operations and 1 component, got %+v", orders)
+	}
+	if missing.Status != http.StatusServiceUnavailable || missing.Error == "" || missing.Stale || missing.LastSuccess != nil {
+		t.Errorf("expected a failed fetch, got %+v", missing)
+	}
+
+	down.Store(true)
+	report = getJSON[*swagger.StatusReport](t, handler, "/api/v1/docs/_status")
+	if len(report.Sources) != 2 {
+		t.Fatalf("expected 2 sources, got %+v", report.Sources)
+	}
+	if orders := report.Sources[0]; !orders.Stale || orders.Error == "" || orders.Operations != 2 || orders.LastSuccess == nil {
+		t.Errorf("expected a stale copy, got %+v", orders)
+	}
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest("GET", "/api/v1/docs/swagger.yaml", nil))
+	if !strings.Contains(rw.Body.String(), "/orders") {
+		t.Errorf("expected the stale copy to be merged, got %s", rw.Body.String())
+	}
+}
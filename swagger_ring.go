@@ -8,20 +8,68 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"os"
+	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 	"text/template"
+	"time"
 
-	"github.com/usalko/swagger-ring/docs"
 	"gopkg.in/yaml.v3"
 )
 
 // Config is the plugin configuration.
 type Config struct {
-	Path string     `json:"path"`
-	Docs []*DocPath `json:"docs"`
+	// Path is the path the documentation page is served at, the documents are served below it.
+	Path string `json:"path"`
+	// PathRegex is a regular expression matching the beginning of the request path
+	// when the documentation is served at several paths, e.g. `^/api/v[0-9]+/docs`.
+	// The matched part takes the place of Path.
+	PathRegex string `json:"pathRegex"`
+	// YAMLFile is the file name of the merged document in YAML, swagger.yaml by default.
+	YAMLFile string `json:"yamlFile"`
+	// JSONFile is the file name of the merged document in JSON, swagger.json by default.
+	JSONFile string     `json:"jsonFile"`
+	Docs     []*DocPath `json:"docs"`
+	// Overlays are OpenAPI Overlay documents applied to the merged document.
+	Overlays []*Overlay `json:"overlays"`
+	// CacheTTL is how long a rendered document is reused, e.g. "30s". Empty disables the cache.
+	CacheTTL string `json:"cacheTtl"`
+	// DereferenceDepth limits how deep `$ref`s are inlined for `?dereference=true`. Zero means unlimited.
+	DereferenceDepth int `json:"dereferenceDepth"`
+	// FailOnInvalid stops serving the merged document when it does not pass validation.
+	FailOnInvalid bool `json:"failOnInvalid"`
+	// Lint configures the guideline rules reported by the lint endpoint.
+	Lint *LintConfig `json:"lint"`
+	// History configures the version history of the merged document.
+	History *HistoryConfig `json:"history"`
+	// AnnotateSources adds the x-swagger-ring-source extension to every operation and component.
+	AnnotateSources bool `json:"annotateSources"`
+	// UI configures the documentation page.
+	UI *UIConfig `json:"ui"`
+	// Auth restricts the documentation page and endpoints.
+	Auth *AuthConfig `json:"auth"`
+	// Mock answers the requests of the merged document operations with their examples.
+	Mock *MockConfig `json:"mock"`
+	// RequestValidation validates the API requests against the merged document before forwarding them.
+	RequestValidation *RequestValidationConfig `json:"requestValidation"`
+	// ResponseValidation checks a sample of the API responses against the merged document.
+	ResponseValidation *ResponseValidationConfig `json:"responseValidation"`
+	// Coverage records which operations of the merged document receive API traffic.
+	Coverage *CoverageConfig `json:"coverage"`
+	// Deprecation adds deprecation headers to the responses of deprecated operations.
+	Deprecation *DeprecationConfig `json:"deprecation"`
+	// OperationHeaders tags the API requests with the operationId, the source and the path template of their operation.
+	OperationHeaders *OperationHeadersConfig `json:"operationHeaders"`
+	// Log configures the plugin logs.
+	Log *LogConfig `json:"log"`
+	// MetricsPath is the route below Path the Prometheus metrics are served at, e.g. /metrics.
+	// Empty disables the metrics.
+	MetricsPath string `json:"metricsPath"`
 }
 
 type DocType int
@@ -31,10 +79,14 @@ const (
 	DOC_TYPE_JSON
 )
 
-// DocPath is a path configuration.
+// DocPath is a path configuration. It references a document to merge, or answers
+// the matching requests with a static response when Content, JSONData, PathRegex or Status is set.
 type DocPath struct {
-	// Path is the exact path to match.
+	// Path is the URL of the document to merge, or the exact path to match for a static response.
 	Path string `json:"path"`
+	// Name identifies the document in the UI and at `{path}/sources/{name}.yaml`.
+	// The default is the host of the document URL.
+	Name string `json:"name"`
 	// PathRegex is a regular expression to match.
 	PathRegex string `json:"pathRegex"`
 	// Content is a go template of content to serve.
@@ -45,10 +97,14 @@ type DocPath struct {
 	Indent int `json:"indent"`
 	// Status is the HTTP status code to return.
 	Status int `json:"status"`
+	// Overlays are OpenAPI Overlay documents applied to this document before merging.
+	Overlays []*Overlay `json:"overlays"`
 
 	pathRegex *regexp.Regexp
 	template  *template.Template
 	jsonData  []byte
+	overlays  []*overlayDocument
+	state     *sourceState
 }
 
 // CreateConfig creates the default plugin configuration.
@@ -58,94 +114,471 @@ func CreateConfig() *Config {
 	}
 }
 
+// ParseConfig reads a plugin configuration written in YAML or JSON, with the keys Traefik passes to the plugin.
+func ParseConfig(data []byte) (*Config, error) {
+	var document any
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("wrong configuration format: %w", err)
+	}
+	config := CreateConfig()
+	if document == nil {
+		return config, nil
+	}
+	normalized, err := json.Marshal(normalizeDocument(document))
+	if err != nil {
+		return nil, fmt.Errorf("wrong configuration format: %w", err)
+	}
+	if err := json.Unmarshal(normalized, config); err != nil {
+		return nil, fmt.Errorf("wrong configuration: %w", err)
+	}
+	return config, nil
+}
+
+const (
+	defaultYAMLFile = "swagger.yaml"
+	defaultJSONFile = "swagger.json"
+	// defaultFetchTimeout bounds the fetch of a source document, so a hung source cannot hold the merge
+	defaultFetchTimeout = 10 * time.Second
+)
+
 // SwaggerRing is a plugin that merge multiply swagger docs into unified
 type SwaggerRing struct {
 	next          http.Handler
+	client        *http.Client
 	path          string
+	rootPath      bool
 	pathRegexp    *regexp.Regexp
+	yamlFile      string
+	jsonFile      string
+	ui            *UIConfig
 	refs          []DocPath
+	statics       []DocPath
 	name          string
 	staticContent []byte
+	overlays      []*overlayDocument
+	cache         *documentCache
+	derefDepth    int
+	failOnInvalid bool
+	lintRules     []*LintRule
+	versions      *specTracker
+	annotate      bool
+	assets        http.Handler
+	auth          *authenticator
+	metricsPath   string
+	metrics       *metrics
+	logger        *slog.Logger
+	operations    *operationIndex
+	mock          *mocker
+	requests      *requestValidator
+	responses     *responseValidator
+	coverage      *coverageRecorder
+	deprecation   *deprecationHeaders
+	tags          *operationHeaders
 }
 
 // New creates a new StaticResponse plugin.
 func New(_ context.Context, next http.Handler, config *Config, name string) (http.Handler, error) {
-	jsonConfig, _ := json.Marshal(config)
-	log.Default().Printf("⭕swagger-ring configuration: %v", string(jsonConfig))
+	logger, err := newLogger(config.Log, name, os.Stderr)
+	if err != nil {
+		return nil, err
+	}
+	logger.Debug("configuration loaded", "source", "config", "config", redactConfig(config))
 
 	if len(config.Docs) == 0 {
-		return nil, fmt.Errorf("⭕docs cannot be empty")
+		return nil, fmt.Errorf("docs cannot be empty")
+	}
+	refs := make([]DocPath, 0, len(config.Docs))
+	statics := make([]DocPath, 0, len(config.Docs))
+	for _, docPath := range config.Docs {
+		ref := *docPath
+		if ref.isStatic() {
+			if err := ref.compile(); err != nil {
+				return nil, fmt.Errorf("invalid path configuration %s%s: %w", docPath.Path, docPath.PathRegex, err)
+			}
+			statics = append(statics, ref)
+			continue
+		}
+		overlays, err := loadOverlays(ref.Overlays)
+		if err != nil {
+			return nil, fmt.Errorf("invalid overlays for %s: %w", docPath.Path, err)
+		}
+		ref.overlays = overlays
+		ref.state = &sourceState{}
+		refs = append(refs, ref)
+	}
+	if err := assignSourceNames(refs); err != nil {
+		return nil, fmt.Errorf("invalid docs: %w", err)
+	}
+	overlays, err := loadOverlays(config.Overlays)
+	if err != nil {
+		return nil, fmt.Errorf("invalid overlays: %w", err)
+	}
+	cacheTTL := time.Duration(0)
+	if config.CacheTTL != "" {
+		cacheTTL, err = time.ParseDuration(config.CacheTTL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cacheTtl %v: %w", config.CacheTTL, err)
+		}
+	}
+	lintRules, err := compileLintRules(config.Lint)
+	if err != nil {
+		return nil, err
+	}
+	history, err := newSpecHistory(config.History)
+	if err != nil {
+		return nil, fmt.Errorf("invalid history: %w", err)
+	}
+	yamlFile, jsonFile, err := documentFiles(config)
+	if err != nil {
+		return nil, err
 	}
-	refs := make([]DocPath, len(config.Docs))
-	for i, docPath := range config.Docs {
-		ref := docPath
-		// if err := ref.compile(); err != nil {
-		// 	return nil, fmt.Errorf("invalid path configuration %s: %w", docPath.Path, err)
-		// }
-		refs[i] = *ref
+	// A trailing slash is dropped, "/" serves the documentation at the root of the host
+	path := strings.TrimRight(config.Path, "/")
+	rootPath := config.Path != "" && path == ""
+	pageURL := path
+	if rootPath {
+		pageURL = "/"
 	}
-	pathRegexp, err := regexp.Compile(config.Path)
+	indexPage, err := renderIndexPage(path, config.UI, refs, yamlFile, jsonFile)
 	if err != nil {
-		log.Default().Printf("⭕path is not regexp %v", err)
+		return nil, err
+	}
+	assets, err := newAssetsHandler(config.UI)
+	if err != nil {
+		return nil, err
+	}
+	auth, err := newAuthenticator(config.Auth, name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid auth: %w", err)
+	}
+	if config.MetricsPath != "" && !strings.HasPrefix(config.MetricsPath, "/") {
+		return nil, fmt.Errorf("invalid metricsPath %v, it must start with /", config.MetricsPath)
+	}
+	collector := newMetrics()
+	mock, err := newMocker(config.Mock, refs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mock: %w", err)
+	}
+	requests, err := newRequestValidator(config.RequestValidation)
+	if err != nil {
+		return nil, fmt.Errorf("invalid requestValidation: %w", err)
+	}
+	responses, err := newResponseValidator(config.ResponseValidation)
+	if err != nil {
+		return nil, fmt.Errorf("invalid responseValidation: %w", err)
+	}
+	tags, err := newOperationHeaders(config.OperationHeaders)
+	if err != nil {
+		return nil, fmt.Errorf("invalid operationHeaders: %w", err)
+	}
+	operationsTTL := cacheTTL
+	if operationsTTL <= 0 {
+		operationsTTL = defaultOperationIndexTTL
+	}
+	var pathRegexp *regexp.Regexp
+	if config.PathRegex != "" {
+		pathRegexp, err = regexp.Compile(config.PathRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pathRegex %v: %w", config.PathRegex, err)
+		}
 	}
 
 	return &SwaggerRing{
-		path:          config.Path,
+		path:          path,
+		rootPath:      rootPath,
 		pathRegexp:    pathRegexp,
+		yamlFile:      yamlFile,
+		jsonFile:      jsonFile,
+		ui:            config.UI,
 		refs:          refs,
+		statics:       statics,
 		next:          next,
+		client:        &http.Client{Timeout: defaultFetchTimeout},
 		name:          name,
-		staticContent: docs.IndexHtml,
+		staticContent: indexPage,
+		overlays:      overlays,
+		cache:         newDocumentCache(cacheTTL, collector),
+		derefDepth:    config.DereferenceDepth,
+		failOnInvalid: config.FailOnInvalid,
+		lintRules:     lintRules,
+		versions:      &specTracker{history: history},
+		annotate:      config.AnnotateSources,
+		assets:        assets,
+		auth:          auth,
+		metricsPath:   config.MetricsPath,
+		metrics:       collector,
+		logger:        logger,
+		operations:    newOperationIndex(operationsTTL),
+		mock:          mock,
+		requests:      requests,
+		responses:     responses,
+		coverage:      newCoverageRecorder(config.Coverage),
+		deprecation:   newDeprecationHeaders(config.Deprecation, pageURL),
+		tags:          tags,
 	}, nil
 }
 
-func (swaggerMerger *SwaggerRing) GetMergedSwaggerDoc(docType DocType) (string, error) {
-	// log.Default().Printf("⭕refs are %v", swaggerMerger.refs)
-	result := make(map[any]any, 0)
-	for _, ref := range swaggerMerger.refs {
-		// Get the data
-		resp, err := http.Get(ref.Path)
+// documentFiles returns the file names the merged document is served at.
+func documentFiles(config *Config) (string, string, error) {
+	yamlFile, jsonFile := config.YAMLFile, config.JSONFile
+	if yamlFile == "" {
+		yamlFile = defaultYAMLFile
+	}
+	if jsonFile == "" {
+		jsonFile = defaultJSONFile
+	}
+	for _, file := range []string{yamlFile, jsonFile} {
+		if strings.Contains(file, "/") {
+			return "", "", fmt.Errorf("invalid document file name %q", file)
+		}
+	}
+	if yamlFile == jsonFile {
+		return "", "", fmt.Errorf("yamlFile and jsonFile cannot be the same %q", yamlFile)
+	}
+	return yamlFile, jsonFile, nil
+}
+
+// fetchDocument loads the document referenced by the DocPath and applies its overlays.
+// When the document cannot be loaded the last good copy is returned, if there is one.
+func (swaggerMerger *SwaggerRing) fetchDocument(ref *DocPath) (map[string]any, error) {
+	start := time.Now()
+	fetched := &SourceStatus{Name: ref.Name, URL: redactURL(ref.Path), FetchedAt: start.UTC()}
+	swagger, err := swaggerMerger.loadDocument(ref, fetched)
+	fetched.LatencyMS = float64(time.Since(start).Microseconds()) / 1000
+	swaggerMerger.metrics.observe(swaggerMerger.metrics.fetchDuration, start, ref.Name)
+
+	swagger, stale := ref.state.update(fetched, swagger, err)
+	if stale {
+		swaggerMerger.logger.Warn("serving stale copy", "source", ref.Name, "error", err)
+		return swagger, nil
+	}
+	return swagger, err
+}
+
+// loadDocument fetches and parses the document and records the response in the status.
+func (swaggerMerger *SwaggerRing) loadDocument(ref *DocPath, fetched *SourceStatus) (map[string]any, error) {
+	data, err := swaggerMerger.readDocument(ref, fetched)
+	if err != nil {
+		return nil, err
+	}
+
+	var swagger map[string]any
+	switch {
+	case strings.HasSuffix(ref.Path, ".yml") || strings.HasSuffix(ref.Path, ".yaml"):
+		fetched.Format = "yaml"
+		if err = yaml.Unmarshal(data, &swagger); err != nil {
+			swaggerMerger.metrics.inc(swaggerMerger.metrics.fetchErrors, ref.Name, "parse")
+			swaggerMerger.metrics.inc(swaggerMerger.metrics.parseFailures, ref.Name, "yaml")
+			return nil, fmt.Errorf("wrong yaml document format issue: %w", err)
+		}
+	case strings.HasSuffix(ref.Path, ".json"):
+		fetched.Format = "json"
+		if err = json.Unmarshal(data, &swagger); err != nil {
+			swaggerMerger.metrics.inc(swaggerMerger.metrics.fetchErrors, ref.Name, "parse")
+			swaggerMerger.metrics.inc(swaggerMerger.metrics.parseFailures, ref.Name, "json")
+			return nil, fmt.Errorf("wrong json document format issue: %w", err)
+		}
+	default:
+		swaggerMerger.metrics.inc(swaggerMerger.metrics.fetchErrors, ref.Name, "format")
+		return nil, fmt.Errorf("unknown document format %v", redactURL(ref.Path))
+	}
+
+	swagger = normalizeDocument(swagger).(map[string]any)
+	if err = applyOverlays(swagger, ref.overlays); err != nil {
+		swaggerMerger.metrics.inc(swaggerMerger.metrics.fetchErrors, ref.Name, "overlay")
+		return nil, fmt.Errorf("overlay issue: %w", err)
+	}
+	return swagger, nil
+}
+
+// readDocument reads the document from its URL, or from the local file system
+// for `file://` URLs and paths without a scheme.
+func (swaggerMerger *SwaggerRing) readDocument(ref *DocPath, fetched *SourceStatus) ([]byte, error) {
+	if file, ok := localDocumentPath(ref.Path); ok {
+		data, err := os.ReadFile(file)
+		fetched.Size = len(data)
 		if err != nil {
-			log.Default().Printf("💍 error get an document by path %v (%v)", ref.Path, err)
-			continue
+			swaggerMerger.metrics.inc(swaggerMerger.metrics.fetchErrors, ref.Name, "read")
+			return nil, fmt.Errorf("error read a document by path %v (%w)", file, err)
 		}
-		defer resp.Body.Close()
+		return data, nil
+	}
 
-		buf := bytes.NewBufferString("")
-		// Writer the body to file
-		_, err = io.Copy(buf, resp.Body)
+	// Get the data
+	resp, err := swaggerMerger.client.Get(ref.Path)
+	if err != nil {
+		swaggerMerger.metrics.inc(swaggerMerger.metrics.fetchErrors, ref.Name, "request")
+		if urlErr, ok := err.(*url.Error); ok {
+			urlErr.URL = redactURL(urlErr.URL)
+		}
+		return nil, fmt.Errorf("error get an document by path %v (%w)", redactURL(ref.Path), err)
+	}
+	defer resp.Body.Close()
+	fetched.Status = resp.StatusCode
+
+	buf := bytes.NewBufferString("")
+	// Writer the body to file
+	_, err = io.Copy(buf, resp.Body)
+	fetched.Size = buf.Len()
+	if err != nil {
+		swaggerMerger.metrics.inc(swaggerMerger.metrics.fetchErrors, ref.Name, "read")
+		return nil, fmt.Errorf("error get body issue: %w", err)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		swaggerMerger.metrics.inc(swaggerMerger.metrics.fetchErrors, ref.Name, "status")
+		return nil, fmt.Errorf("unexpected status %d of %v", resp.StatusCode, redactURL(ref.Path))
+	}
+	return buf.Bytes(), nil
+}
+
+func localDocumentPath(path string) (string, bool) {
+	if strings.HasPrefix(path, "file://") {
+		return strings.TrimPrefix(path, "file://"), true
+	}
+	return path, !strings.Contains(path, "://")
+}
+
+// mergedDocument is the merged document together with the sources of its values.
+type mergedDocument struct {
+	document   map[string]any
+	provenance *provenance
+}
+
+// buildMergedDocument fetches every configured document and merges them into one.
+func (swaggerMerger *SwaggerRing) buildMergedDocument() (*mergedDocument, error) {
+	defer swaggerMerger.metrics.observe(swaggerMerger.metrics.mergeDuration, time.Now())
+	merged := &mergedDocument{document: make(map[string]any, 0), provenance: newProvenance()}
+	for i := range swaggerMerger.refs {
+		swagger, err := swaggerMerger.fetchDocument(&swaggerMerger.refs[i])
 		if err != nil {
-			log.Default().Printf("💍 error get body issue: %v", err)
+			swaggerMerger.logger.Warn("source skipped", "source", swaggerMerger.refs[i].Name, "error", err)
 			continue
 		}
-		if strings.HasSuffix(ref.Path, ".yml") || strings.HasSuffix(ref.Path, ".yaml") {
-			var swagger map[any]any
-			err = yaml.Unmarshal(buf.Bytes(), &swagger)
-			if err != nil {
-				log.Default().Printf("💍 wrong yaml document format issue: %v", err)
-				continue
+		merged.provenance.source = swaggerMerger.refs[i].Name
+		swaggerMerger.deepRing(merged.document, deepCopy(swagger).(map[string]any), "", merged.provenance)
+	}
+	if err := applyOverlays(merged.document, swaggerMerger.overlays); err != nil {
+		return nil, fmt.Errorf("overlay issue: %w", err)
+	}
+	if swaggerMerger.annotate {
+		merged.annotateSources()
+	}
+	if report := swaggerMerger.versions.observe(merged.document); report != nil {
+		swaggerMerger.logger.Info("merged document changed", "source", "merged", "from", report.From, "to", report.To,
+			"changes", len(report.Changes), "breaking", report.Breaking)
+		for _, change := range report.Changes {
+			if change.Breaking {
+				swaggerMerger.logger.Warn("breaking change", "source", "merged", "pointer", change.Pointer, "message", change.Message)
 			}
-			swaggerMerger.deepRing(result, swagger)
-			continue
 		}
-		if strings.HasSuffix(ref.Path, ".json") {
-			var swagger map[any]any
-			err = json.Unmarshal(buf.Bytes(), &swagger)
-			if err != nil {
-				log.Default().Printf("💍 wrong json document format issue: %v", err)
-				continue
-			}
-			swaggerMerger.deepRing(result, swagger)
-			continue
+	}
+	return merged, nil
+}
+
+// currentMergedDocument returns the merged document, reused for cacheTtl by every document,
+// report and the operation index. The result is shared and must not be modified.
+func (swaggerMerger *SwaggerRing) currentMergedDocument() (*mergedDocument, error) {
+	merged, err := swaggerMerger.cache.getValue("sources", func() (any, error) {
+		merged, err := swaggerMerger.buildMergedDocument()
+		if err != nil {
+			return nil, err
 		}
+		return merged, nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return merged.(*mergedDocument), nil
+}
 
-	if docType == DOC_TYPE_YAML {
-		// Корректируем ссылки
-		for key, value := range result {
-			result[key] = swaggerMerger.referencesCorrection(key, value)
+// loadMergedDocument builds the merged document and refuses to publish it
+// when it is invalid and the configuration asks to fail closed.
+func (swaggerMerger *SwaggerRing) loadMergedDocument() (map[string]any, error) {
+	merged, err := swaggerMerger.currentMergedDocument()
+	if err != nil {
+		return nil, err
+	}
+	if swaggerMerger.failOnInvalid {
+		if report := validateDocument(merged); !report.Valid {
+			swaggerMerger.logger.Error("merged document is invalid", "source", "merged", "errors", len(report.Errors))
+			return nil, fmt.Errorf("merged document is invalid: %d errors, see %v/validation", len(report.Errors), swaggerMerger.path)
 		}
+	}
+	return merged.document, nil
+}
 
+// GetMergedSwaggerDoc returns the merged document rendered as YAML or JSON.
+func (swaggerMerger *SwaggerRing) GetMergedSwaggerDoc(docType DocType) (string, error) {
+	return swaggerMerger.cache.get(fmt.Sprintf("merged.%d", docType), func() (string, error) {
+		result, err := swaggerMerger.loadMergedDocument()
+		if err != nil {
+			return "", err
+		}
+		return swaggerMerger.renderDocument(result, docType)
+	})
+}
+
+// GetDereferencedSwaggerDoc returns the merged document with every internal `$ref` inlined.
+func (swaggerMerger *SwaggerRing) GetDereferencedSwaggerDoc(docType DocType) (string, error) {
+	return swaggerMerger.cache.get(fmt.Sprintf("dereferenced.%d", docType), func() (string, error) {
+		result, err := swaggerMerger.loadMergedDocument()
+		if err != nil {
+			return "", err
+		}
+		return swaggerMerger.renderDocument(dereferenceDocument(result, swaggerMerger.derefDepth), docType)
+	})
+}
+
+// GetValidationReport returns the validation report of the merged document as JSON.
+func (swaggerMerger *SwaggerRing) GetValidationReport() (string, error) {
+	return swaggerMerger.cache.get("validation", func() (string, error) {
+		merged, err := swaggerMerger.currentMergedDocument()
+		if err != nil {
+			return "", err
+		}
+		report, err := json.Marshal(validateDocument(merged))
+		if err != nil {
+			return "", err
+		}
+		return string(report), nil
+	})
+}
+
+// GetLintReport returns the lint report of the merged document as JSON.
+func (swaggerMerger *SwaggerRing) GetLintReport() (string, error) {
+	return swaggerMerger.cache.get("lint", func() (string, error) {
+		merged, err := swaggerMerger.currentMergedDocument()
+		if err != nil {
+			return "", err
+		}
+		report, err := json.Marshal(lintDocument(merged, swaggerMerger.lintRules))
+		if err != nil {
+			return "", err
+		}
+		return string(report), nil
+	})
+}
+
+// GetChangeReport returns the changes between the two latest versions of the merged document as JSON.
+func (swaggerMerger *SwaggerRing) GetChangeReport() (string, error) {
+	// Refresh the merged document to pick up a new version
+	if _, err := swaggerMerger.GetMergedSwaggerDoc(DOC_TYPE_JSON); err != nil {
+		return "", err
+	}
+	changes := swaggerMerger.versions.latest()
+	if changes == nil {
+		changes = &ChangeReport{Changes: make([]*SpecChange, 0)}
+	}
+	report, err := json.Marshal(changes)
+	if err != nil {
+		return "", err
+	}
+	return string(report), nil
+}
+
+// renderDocument marshals the document as YAML or JSON.
+func (swaggerMerger *SwaggerRing) renderDocument(result map[string]any, docType DocType) (string, error) {
+	if docType == DOC_TYPE_YAML {
 		mergedDoc, err := yaml.Marshal(result)
 		if err != nil {
 			return "", err
@@ -164,105 +597,255 @@ func (swaggerMerger *SwaggerRing) GetMergedSwaggerDoc(docType DocType) (string,
 
 func (swaggerMerger *SwaggerRing) appendIfMissing(slice []any, newElement any) []any {
 	for _, element := range slice {
-		if element == newElement {
+		if reflect.DeepEqual(element, newElement) {
 			return slice
 		}
 	}
 	return append(slice, newElement)
 }
 
-// deepRing рекурсивно объединяет два YAML/JSON-объекта
-func (swaggerMerger *SwaggerRing) deepRing(dst, src map[any]any) {
+// deepRing recursively merges two YAML/JSON objects
+// and records the source of every merged value in the trace.
+func (swaggerMerger *SwaggerRing) deepRing(dst, src map[string]any, pointer string, trace *provenance) {
 	for key, srcVal := range src {
-		// Если ключ уже есть в dst
+		childPointer := joinPointer(pointer, key)
+		// The key is already in dst
 		if dstVal, exists := dst[key]; exists {
-			// log.Default().Printf("🔥 dstVal for %v is %T", key, dstVal)
-			// log.Default().Printf("🔥 srcVal for %v is %T", key, srcVal)
-			// Если оба значения — map, рекурсивно объединяем
-			if dstMap, ok := dstVal.(map[any]any); ok {
-				if srcMap, ok := srcVal.(map[any]any); ok {
-					swaggerMerger.deepRing(dstMap, srcMap)
+			// Both values are maps, merge them recursively
+			if dstMap, ok := dstVal.(map[string]any); ok {
+				if srcMap, ok := srcVal.(map[string]any); ok {
+					trace.contribute(childPointer)
+					swaggerMerger.deepRing(dstMap, srcMap, childPointer, trace)
 					dst[key] = dstMap
 					continue
 				}
 			}
-			// Если оба значения — slice, объединяем оставляя уникальные
+			// Both values are slices, keep the unique elements of both
 			if dstSlice, ok := dstVal.([]any); ok {
 				if srcSlice, ok := srcVal.([]any); ok {
+					trace.contribute(childPointer)
 					slicesUnion := append([]any{}, dstSlice...)
 					for _, element := range srcSlice {
+						size := len(slicesUnion)
 						slicesUnion = swaggerMerger.appendIfMissing(slicesUnion, element)
+						if len(slicesUnion) > size {
+							trace.replace(joinPointer(childPointer, size), element)
+						}
 					}
 					dst[key] = slicesUnion
 					continue
 				}
 			}
 		}
-		// Иначе просто перезаписываем
+		// Otherwise overwrite the value
+		trace.replace(childPointer, srcVal)
 		dst[key] = srcVal
 	}
 }
 
-func (swaggerMerger *SwaggerRing) referencesCorrection(key any, value any) any {
-	// Map case
-	if srcMap, ok := value.(map[any]any); ok {
-		for childKey, childValue := range srcMap {
-			srcMap[fmt.Sprintf("%v", childKey)] = swaggerMerger.referencesCorrection(childKey, childValue)
+// route splits the request path into the documentation root, Path or the part matched
+// by PathRegex, and the route below it. Requests outside the root are not documentation requests.
+func (swaggerMerger *SwaggerRing) route(requestPath string) (string, string, bool) {
+	path := swaggerMerger.path
+	if swaggerMerger.rootPath {
+		if requestPath == "/" {
+			return "", "", true
 		}
+		return "", requestPath, true
 	}
-	// Slice case
-	if srcSlice, ok := value.([]any); ok {
-		for _, sliceElement := range srcSlice {
-			if srcMap, ok := sliceElement.(map[any]any); ok {
-				for childKey, childValue := range srcMap {
-					srcMap[fmt.Sprintf("%v", childKey)] = swaggerMerger.referencesCorrection(childKey, childValue)
-				}
+	if path != "" && (requestPath == path || strings.HasPrefix(requestPath, path+"/")) {
+		return path, strings.TrimPrefix(requestPath, path), true
+	}
+	if swaggerMerger.pathRegexp != nil {
+		if loc := swaggerMerger.pathRegexp.FindStringIndex(requestPath); loc != nil && loc[0] == 0 {
+			root, rest := requestPath[:loc[1]], requestPath[loc[1]:]
+			if root != "" && (rest == "" || strings.HasPrefix(rest, "/")) {
+				return root, rest, true
 			}
 		}
 	}
+	return "", "", false
+}
 
-	// keys trigger
-	if key == "$ref" {
-		return fmt.Sprintf("'%v'", value)
+// ServeHTTP implements the http.Handler interface.
+func (swaggerMerger *SwaggerRing) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	for i := range swaggerMerger.statics {
+		if ref := &swaggerMerger.statics[i]; ref.matches(req.URL.Path) {
+			swaggerMerger.serveEndpoint(rw, req, "static", false, func(rw http.ResponseWriter) {
+				swaggerMerger.serveStatic(rw, req, ref)
+			})
+			return
+		}
 	}
 
-	if description, ok := value.(string); key == "description" && ok && strings.IndexAny(description, "':#") > -1 {
-		return fmt.Sprintf("'%v'", strings.ReplaceAll(fmt.Sprint(value), "'", "`"))
+	root, route, ok := swaggerMerger.route(req.URL.Path)
+	if !ok {
+		swaggerMerger.serveAPI(rw, req)
+		return
 	}
-
-	return value
+	endpoint, serve := swaggerMerger.endpoint(root, route, req)
+	if serve == nil {
+		swaggerMerger.serveAPI(rw, req)
+		return
+	}
+	swaggerMerger.serveEndpoint(rw, req, endpoint, true, serve)
 }
 
-// ServeHTTP implements the http.Handler interface.
-func (swaggerMerger *SwaggerRing) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
-	path := swaggerMerger.path
+// serveAPI handles a request that is not a documentation request. It is matched to an operation
+// of the merged document when a feature needs it, validated, and mocked or passed to the next handler,
+// whose response is checked when it is sampled.
+func (swaggerMerger *SwaggerRing) serveAPI(rw http.ResponseWriter, req *http.Request) {
+	var match *operationMatch
+	shadowed := swaggerMerger.responses.sampled()
+	if swaggerMerger.needsOperation(req, shadowed) {
+		match = swaggerMerger.matchOperation(req)
+	}
+	if swaggerMerger.coverage != nil {
+		routes, _ := swaggerMerger.operationRoutes()
+		swaggerMerger.coverage.record(req, match, routes)
+	}
+	if swaggerMerger.tags != nil {
+		swaggerMerger.tags.set(req, match)
+	}
+	if match == nil {
+		swaggerMerger.next.ServeHTTP(rw, req)
+		return
+	}
+	if swaggerMerger.requests != nil && !swaggerMerger.checkRequest(rw, req, match) {
+		return
+	}
+	if swaggerMerger.deprecation != nil {
+		swaggerMerger.deprecation.set(rw.Header(), match)
+	}
+	if swaggerMerger.mock.enabled(req) && swaggerMerger.mock.mocks(req, match) {
+		swaggerMerger.serveEndpoint(rw, req, "mock", false, func(rw http.ResponseWriter) {
+			swaggerMerger.serveMock(rw, req, match)
+		})
+		return
+	}
+	if shadowed {
+		swaggerMerger.serveShadowed(rw, req, match)
+		return
+	}
+	swaggerMerger.next.ServeHTTP(rw, req)
+}
 
-	// log.Default().Printf("⭕request.path is %v, path is %v", req.URL.Path, path)
+// needsOperation tells whether a feature needs the operation of the API request.
+func (swaggerMerger *SwaggerRing) needsOperation(req *http.Request, shadowed bool) bool {
+	return shadowed || swaggerMerger.mock.enabled(req) || swaggerMerger.requests != nil ||
+		swaggerMerger.coverage != nil || swaggerMerger.deprecation != nil || swaggerMerger.tags != nil
+}
 
-	if path != "" && (path == req.URL.Path) {
-		if len(swaggerMerger.staticContent) > 0 {
-			rw.Header().Set("Content-Type", "text/html")
-			fmt.Fprint(rw, string(swaggerMerger.staticContent))
+// endpoint returns the name and the handler of the documentation endpoint at the route below the root.
+// The handler is nil when the route is not a documentation endpoint.
+func (swaggerMerger *SwaggerRing) endpoint(root, route string, req *http.Request) (string, func(http.ResponseWriter)) {
+	switch {
+	case route == "":
+		return "page", func(rw http.ResponseWriter) { swaggerMerger.serveIndexPage(rw, root) }
+	case swaggerMerger.metricsPath != "" && route == swaggerMerger.metricsPath:
+		return "metrics", swaggerMerger.serveMetrics
+	case swaggerMerger.assets != nil && strings.HasPrefix(route, "/assets/"):
+		return "assets", func(rw http.ResponseWriter) {
+			http.StripPrefix(root+"/assets/", swaggerMerger.assets).ServeHTTP(rw, req)
 		}
-		return
+	case route == "/_status":
+		return "status", func(rw http.ResponseWriter) { swaggerMerger.serveReport(rw, swaggerMerger.GetStatusReport) }
+	case swaggerMerger.coverage != nil && route == "/coverage":
+		return "coverage", func(rw http.ResponseWriter) { swaggerMerger.serveReport(rw, swaggerMerger.GetCoverageReport) }
+	case route == "/validation":
+		return "validation", func(rw http.ResponseWriter) { swaggerMerger.serveReport(rw, swaggerMerger.GetValidationReport) }
+	case route == "/lint":
+		return "lint", func(rw http.ResponseWriter) { swaggerMerger.serveReport(rw, swaggerMerger.GetLintReport) }
+	case route == "/changes":
+		return "changes", func(rw http.ResponseWriter) { swaggerMerger.serveReport(rw, swaggerMerger.GetChangeReport) }
+	case route == "/provenance":
+		return "provenance", func(rw http.ResponseWriter) { swaggerMerger.serveReport(rw, swaggerMerger.GetProvenance) }
+	case strings.HasPrefix(route, "/sources/"):
+		return "sources", func(rw http.ResponseWriter) {
+			swaggerMerger.serveSource(rw, strings.TrimPrefix(route, "/sources/"))
+		}
+	case route == "/history":
+		return "history", func(rw http.ResponseWriter) { swaggerMerger.serveReport(rw, swaggerMerger.GetHistory) }
+	case strings.HasPrefix(route, "/history/"):
+		return "history", func(rw http.ResponseWriter) {
+			swaggerMerger.serveHistorySnapshot(rw, strings.TrimPrefix(route, "/history/"))
+		}
+	case route == "/"+swaggerMerger.yamlFile:
+		return "document", func(rw http.ResponseWriter) { swaggerMerger.serveDocument(rw, req, DOC_TYPE_YAML) }
+	case route == "/"+swaggerMerger.jsonFile:
+		return "document", func(rw http.ResponseWriter) { swaggerMerger.serveDocument(rw, req, DOC_TYPE_JSON) }
 	}
-	if path != "" && (strings.HasSuffix(req.URL.Path, ".yaml") || strings.HasSuffix(req.URL.Path, ".yml")) {
-		rw.Header().Set("Content-Type", "application/yaml")
-		mergedSwaggerDocument, err := swaggerMerger.GetMergedSwaggerDoc(DOC_TYPE_YAML)
+	return "", nil
+}
+
+// serveEndpoint runs the handler of the endpoint, after the access checks when the endpoint is protected,
+// and counts the request.
+func (swaggerMerger *SwaggerRing) serveEndpoint(rw http.ResponseWriter, req *http.Request, endpoint string, protected bool, serve func(http.ResponseWriter)) {
+	recorder := &statusRecorder{ResponseWriter: rw}
+	if !protected || swaggerMerger.auth.authorize(recorder, req) {
+		serve(recorder)
+	}
+	if recorder.status == 0 {
+		recorder.status = http.StatusOK
+	}
+	swaggerMerger.metrics.inc(swaggerMerger.metrics.requests, endpoint, strconv.Itoa(recorder.status))
+}
+
+// serveIndexPage writes the documentation page. The page of the configured path is rendered once,
+// the pages of the roots matched by PathRegex are rendered for their own URLs.
+func (swaggerMerger *SwaggerRing) serveIndexPage(rw http.ResponseWriter, root string) {
+	page := swaggerMerger.staticContent
+	if root != swaggerMerger.path {
+		var err error
+		page, err = renderIndexPage(root, swaggerMerger.ui, swaggerMerger.refs, swaggerMerger.yamlFile, swaggerMerger.jsonFile)
 		if err != nil {
 			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
 		}
-		fmt.Fprint(rw, mergedSwaggerDocument)
+	}
+	rw.Header().Set("Content-Type", "text/html")
+	rw.Write(page)
+}
+
+// serveDocument writes the merged document, dereferenced when the request asks for `?dereference=true`.
+func (swaggerMerger *SwaggerRing) serveDocument(rw http.ResponseWriter, req *http.Request, docType DocType) {
+	getDocument := swaggerMerger.GetMergedSwaggerDoc
+	if dereference, _ := strconv.ParseBool(req.URL.Query().Get("dereference")); dereference {
+		getDocument = swaggerMerger.GetDereferencedSwaggerDoc
+	}
+	mergedSwaggerDocument, err := getDocument(docType)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	if path != "" && (strings.HasSuffix(req.URL.Path, ".json")) {
+	writeDocument(rw, mergedSwaggerDocument, docType)
+}
+
+// parseDocumentName splits `name.yaml`, `name.yml` or `name.json` into the name and the document type.
+func parseDocumentName(file string) (string, DocType) {
+	if strings.HasSuffix(file, ".json") {
+		return strings.TrimSuffix(file, ".json"), DOC_TYPE_JSON
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(file, ".yaml"), ".yml"), DOC_TYPE_YAML
+}
+
+// writeDocument writes a rendered document with its content type.
+func writeDocument(rw http.ResponseWriter, document string, docType DocType) {
+	if docType == DOC_TYPE_JSON {
 		rw.Header().Set("Content-Type", "application/json")
-		mergedSwaggerDocument, err := swaggerMerger.GetMergedSwaggerDoc(DOC_TYPE_JSON)
-		if err != nil {
-			http.Error(rw, err.Error(), http.StatusInternalServerError)
-		}
-		fmt.Fprint(rw, mergedSwaggerDocument)
+	} else {
+		rw.Header().Set("Content-Type", "application/yaml")
+	}
+	fmt.Fprint(rw, document)
+}
+
+// serveReport writes a JSON report.
+func (swaggerMerger *SwaggerRing) serveReport(rw http.ResponseWriter, getReport func() (string, error)) {
+	report, err := getReport()
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	swaggerMerger.next.ServeHTTP(rw, req)
+	rw.Header().Set("Content-Type", "application/json")
+	fmt.Fprint(rw, report)
 }
@@ -0,0 +1,113 @@
+package swagger_ring
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/usalko/swagger-ring/docs"
+)
+
+// UIConfig configures the documentation page served at the configured path.
+type UIConfig struct {
+	// Renderer is one of scalar (default), swagger-ui or redoc.
+	Renderer string `json:"renderer"`
+	// Options are passed to the renderer configuration as is.
+	Options map[string]any `json:"options"`
+	// Format is the format of the document loaded by the page, yaml (default) or json.
+	Format string `json:"format"`
+	// Title is the title of the page.
+	Title string `json:"title"`
+	// Logo is the URL of an image shown above the documentation.
+	Logo string `json:"logo"`
+	// Favicon is the URL of the page icon.
+	Favicon string `json:"favicon"`
+	// CSS is a custom style sheet added to the page.
+	CSS string `json:"css"`
+	// AssetsDir is a local directory with the renderer files served at `{path}/assets/`
+	// instead of loading them from the CDN.
+	AssetsDir string `json:"assetsDir"`
+	// Offline turns off every external reference of the page. It requires AssetsDir.
+	Offline bool `json:"offline"`
+	// SourcePicker lets the page switch between the merged document and every source document.
+	// Redoc does not support it.
+	SourcePicker bool `json:"sourcePicker"`
+}
+
+// renderIndexPage renders the documentation page for the documents served under the path.
+func renderIndexPage(path string, config *UIConfig, refs []DocPath, yamlFile, jsonFile string) ([]byte, error) {
+	if config == nil {
+		config = &UIConfig{}
+	}
+	extension, specFile := ".yaml", yamlFile
+	switch config.Format {
+	case "", "yaml":
+	case "json":
+		extension, specFile = ".json", jsonFile
+	default:
+		return nil, fmt.Errorf("invalid ui format %q", config.Format)
+	}
+	specURL := path + "/" + specFile
+	sources := make([]docs.Source, 0, len(refs))
+	if config.SourcePicker {
+		for _, ref := range refs {
+			sources = append(sources, docs.Source{Name: ref.Name, URL: path + "/sources/" + ref.Name + extension})
+		}
+	}
+	assetsURL := ""
+	if config.AssetsDir != "" {
+		assetsURL = path + "/assets"
+	}
+	if config.Offline {
+		if config.AssetsDir == "" {
+			return nil, fmt.Errorf("offline ui requires assetsDir")
+		}
+		for _, url := range []string{config.Logo, config.Favicon} {
+			if isExternalURL(url) {
+				return nil, fmt.Errorf("offline ui cannot reference %v", url)
+			}
+		}
+	}
+	options, _ := normalizeDocument(config.Options).(map[string]any)
+	page, err := docs.IndexPage(&docs.Page{
+		Renderer:  config.Renderer,
+		Options:   options,
+		SpecURL:   specURL,
+		Title:     config.Title,
+		Logo:      config.Logo,
+		Favicon:   config.Favicon,
+		CSS:       config.CSS,
+		AssetsURL: assetsURL,
+		Offline:   config.Offline,
+		Sources:   sources,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid ui: %w", err)
+	}
+	return page, nil
+}
+
+func isExternalURL(url string) bool {
+	return strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") || strings.HasPrefix(url, "//")
+}
+
+// newAssetsHandler serves the renderer files from the configured directory,
+// the `{path}/assets/` prefix is stripped by the caller.
+// It returns nil when the files are loaded from the CDN.
+func newAssetsHandler(config *UIConfig) (http.Handler, error) {
+	if config == nil || config.AssetsDir == "" {
+		return nil, nil
+	}
+	files, err := docs.AssetFiles(config.Renderer, config.SourcePicker)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ui: %w", err)
+	}
+	for _, file := range files {
+		if _, err := os.Stat(filepath.Join(config.AssetsDir, file)); err != nil {
+			return nil, fmt.Errorf("missing ui asset: %w", err)
+		}
+	}
+	return http.FileServer(http.Dir(config.AssetsDir)), nil
+}
@@ -0,0 +1,143 @@
+package swagger_ring_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	swagger "github.com/usalko/swagger-ring"
+)
+
+func TestUI(t *testing.T) {
+	ctx := context.Background()
+
+	tt := []struct {
+		name     string
+		ui       *swagger.UIConfig
+		contains []string
+	}{
+		{
+			name:     "default",
+			contains: []string{"<title>API Documentation</title>", `Scalar.createApiReference('#app', {"url":"/api/v1/docs/swagger.yaml"})`},
+		},
+		{
+			name: "custom page",
+			ui: &swagger.UIConfig{
+				Format:  "json",
+				Title:   "Acme API",
+				Logo:    "/static/logo.svg",
+				Favicon: "/static/favicon.ico",
+				CSS:     "body { margin: 0; }",
+			},
+			contains: []string{
+				"<title>Acme API</title>",
+				`<link rel="icon" href="/static/favicon.ico" />`,
+				`<img src="/static/logo.svg" alt="Acme API" />`,
+				"<style>body { margin: 0; }</style>",
+				`{"url":"/api/v1/docs/swagger.json"}`,
+			},
+		},
+		{
+			name:     "scalar",
+			ui:       &swagger.UIConfig{Renderer: "scalar", Options: map[string]any{"theme": "purple"}},
+			contains: []string{"Scalar.createApiReference('#app', {\"theme\":\"purple\",\"url\":\"/api/v1/docs/swagger.yaml\"})"},
+		},
+		{
+			name: "swagger-ui",
+			ui: &swagger.UIConfig{Renderer: "swagger-ui", Options: map[string]any{
+				"tryItOutEnabled": true,
+				"oauth":           map[string]any{"clientId": "docs"},
+			}},
+			contains: []string{"SwaggerUIBundle", `"tryItOutEnabled":true`, `window.ui.initOAuth({"clientId":"docs"})`},
+		},
+		{
+			name:     "redoc",
+			ui:       &swagger.UIConfig{Renderer: "redoc", Options: map[string]any{"hideDownloadButton": true}},
+			contains: []string{"Redoc.init(\"/api/v1/docs/swagger.yaml\", {\"hideDownloadButton\":true}"},
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := swagger.CreateConfig()
+			cfg.Path = "/api/v1/docs"
+			cfg.UI = tc.ui
+			cfg.Docs = append(cfg.Docs, &swagger.DocPath{Path: "http://service1:3000/swagger.yaml"})
+
+			handler, err := swagger.New(ctx, http.NotFoundHandler(), cfg, "swagger-ring")
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, httptest.NewRequest("GET", "/api/v1/docs", nil))
+			for _, expected := range tc.contains {
+				if !strings.Contains(rw.Body.String(), expected) {
+					t.Errorf("expected page to contain %s, got %s", expected, rw.Body.String())
+				}
+			}
+		})
+	}
+
+	cfg := swagger.CreateConfig()
+	cfg.UI = &swagger.UIConfig{Renderer: "rapidoc"}
+	cfg.Docs = append(cfg.Docs, &swagger.DocPath{Path: "http://service1:3000/swagger.yaml"})
+	if _, err := swagger.New(ctx, http.NotFoundHandler(), cfg, "swagger-ring"); err == nil {
+		t.Error("expected error for unknown renderer, got nil")
+	}
+}
+
+func TestUIAssets(t *testing.T) {
+	ctx := context.Background()
+
+	assetsDir := t.TempDir()
+	for _, file := range []string{"swagger-ui.css", "swagger-ui-bundle.js"} {
+		if err := os.WriteFile(filepath.Join(assetsDir, file), []byte("/* "+file+" */"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := swagger.CreateConfig()
+	cfg.Path = "/docs"
+	cfg.UI = &swagger.UIConfig{Renderer: "swagger-ui", AssetsDir: assetsDir, Offline: true}
+	cfg.Docs = append(cfg.Docs, &swagger.DocPath{Path: "http://service1:3000/swagger.yaml"})
+
+	handler, err := swagger.New(ctx, http.NotFoundHandler(), cfg, "swagger-ring")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest("GET", "/docs", nil))
+	page := rw.Body.String()
+	if strings.Contains(page, "https://") {
+		t.Errorf("expected no external references, got %s", page)
+	}
+	for _, expected := range []string{`href="/docs/assets/swagger-ui.css"`, `src="/docs/assets/swagger-ui-bundle.js"`, `"validatorUrl":null`} {
+		if !strings.Contains(page, expected) {
+			t.Errorf("expected page to contain %s, got %s", expected, page)
+		}
+	}
+
+	rw = httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest("GET", "/docs/assets/swagger-ui-bundle.js", nil))
+	if rw.Code != http.StatusOK || rw.Body.String() != "/* swagger-ui-bundle.js */" {
+		t.Errorf("expected asset content, got %d %s", rw.Code, rw.Body.String())
+	}
+
+	cfg.UI = &swagger.UIConfig{Renderer: "swagger-ui", AssetsDir: assetsDir, SourcePicker: true}
+	if _, err := swagger.New(ctx, http.NotFoundHandler(), cfg, "swagger-ring"); err == nil {
+		t.Error("expected error for missing standalone preset asset, got nil")
+	}
+	cfg.UI = &swagger.UIConfig{Renderer: "redoc", AssetsDir: assetsDir}
+	if _, err := swagger.New(ctx, http.NotFoundHandler(), cfg, "swagger-ring"); err == nil {
+		t.Error("expected error for missing redoc asset, got nil")
+	}
+	cfg.UI = &swagger.UIConfig{Offline: true}
+	if _, err := swagger.New(ctx, http.NotFoundHandler(), cfg, "swagger-ring"); err == nil {
+		t.Error("expected error for offline ui without assets, got nil")
+	}
+}
@@ -0,0 +1,241 @@
+package swagger_ring
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ValidationError is a structural problem found in the merged document.
+type ValidationError struct {
+	// Pointer is the JSON pointer of the invalid element.
+	Pointer string `json:"pointer"`
+	// Message describes the problem.
+	Message string `json:"message"`
+	// Source is the name of the document that produced the element.
+	Source string `json:"source,omitempty"`
+}
+
+// ValidationReport is the result of the merged document validation.
+type ValidationReport struct {
+	Valid  bool               `json:"valid"`
+	Errors []*ValidationError `json:"errors"`
+}
+
+var (
+	httpMethods          = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+	parameterLocations   = map[string]bool{"query": true, "header": true, "path": true, "cookie": true}
+	responseCodePattern  = regexp.MustCompile(`^([1-5][0-9][0-9]|[1-5]XX|default)$`)
+	componentKeyPattern  = regexp.MustCompile(`^[a-zA-Z0-9.\-_]+$`)
+	pathParameterPattern = regexp.MustCompile(`{([^}]+)}`)
+)
+
+// documentValidator collects the structural errors of an OpenAPI 3.x document.
+type documentValidator struct {
+	merged       *mergedDocument
+	errors       []*ValidationError
+	operationIds map[string]string
+}
+
+// validateDocument checks the OpenAPI 3.x structural rules of the merged document.
+func validateDocument(merged *mergedDocument) *ValidationReport {
+	validator := &documentValidator{merged: merged, operationIds: make(map[string]string)}
+	validator.validate(merged.document)
+	sort.SliceStable(validator.errors, func(i, j int) bool {
+		return comparePointers(validator.errors[i].Pointer, validator.errors[j].Pointer) < 0
+	})
+	return &ValidationReport{Valid: len(validator.errors) == 0, Errors: validator.errors}
+}
+
+func (validator *documentValidator) fail(pointer string, format string, args ...any) {
+	validator.errors = append(validator.errors, &ValidationError{
+		Pointer: pointer,
+		Message: fmt.Sprintf(format, args...),
+		Source:  validator.merged.sourceOf(pointer),
+	})
+}
+
+func (validator *documentValidator) object(value any, pointer string) (map[string]any, bool) {
+	node, ok := value.(map[string]any)
+	if !ok {
+		validator.fail(pointer, "must be an object")
+	}
+	return node, ok
+}
+
+func (validator *documentValidator) requireString(node map[string]any, pointer, key string) {
+	value, exists := node[key]
+	if !exists {
+		validator.fail(pointer, "missing required field %q", key)
+		return
+	}
+	if _, ok := value.(string); !ok {
+		validator.fail(joinPointer(pointer, key), "must be a string")
+	}
+}
+
+func (validator *documentValidator) validate(document map[string]any) {
+	version, ok := document["openapi"].(string)
+	if !ok || !strings.HasPrefix(version, "3.") {
+		validator.fail("/openapi", "must be an OpenAPI 3.x version string, got %v", document["openapi"])
+	}
+	if info, ok := validator.object(document["info"], "/info"); ok {
+		validator.requireString(info, "/info", "title")
+		validator.requireString(info, "/info", "version")
+	}
+	if paths, exists := document["paths"]; exists {
+		if paths, ok := validator.object(paths, "/paths"); ok {
+			for _, path := range sortedKeys(paths) {
+				validator.validatePathItem(path, paths[path], joinPointer("/paths", path))
+			}
+		}
+	} else if _, ok := document["webhooks"]; !ok && document["components"] == nil {
+		validator.fail("", "missing required field %q", "paths")
+	}
+	if components, exists := document["components"]; exists {
+		if components, ok := validator.object(components, "/components"); ok {
+			for _, kind := range sortedKeys(components) {
+				group, ok := validator.object(components[kind], joinPointer("/components", kind))
+				if !ok {
+					continue
+				}
+				for _, key := range sortedKeys(group) {
+					if !componentKeyPattern.MatchString(key) {
+						validator.fail(joinPointer(joinPointer("/components", kind), key), "component name must match %v", componentKeyPattern)
+					}
+				}
+			}
+		}
+	}
+	validator.validateReferences(document, "")
+}
+
+func (validator *documentValidator) validatePathItem(path string, value any, pointer string) {
+	if !strings.HasPrefix(path, "/") {
+		validator.fail(pointer, "path must start with /")
+	}
+	item, ok := validator.object(value, pointer)
+	if !ok || item["$ref"] != nil {
+		return
+	}
+	declared := validator.validateParameters(item["parameters"], joinPointer(pointer, "parameters"))
+	for _, method := range httpMethods {
+		operationValue, exists := item[method]
+		if !exists {
+			continue
+		}
+		operationPointer := joinPointer(pointer, method)
+		operation, ok := validator.object(operationValue, operationPointer)
+		if !ok {
+			continue
+		}
+		operationDeclared := validator.validateParameters(operation["parameters"], joinPointer(operationPointer, "parameters"))
+		for _, match := range pathParameterPattern.FindAllStringSubmatch(path, -1) {
+			if !declared[match[1]] && !operationDeclared[match[1]] {
+				validator.fail(operationPointer, "path parameter %q is not declared", match[1])
+			}
+		}
+		if operationId, ok := operation["operationId"].(string); ok {
+			if previous, exists := validator.operationIds[operationId]; exists {
+				validator.fail(joinPointer(operationPointer, "operationId"), "operationId %q is already used by %v", operationId, previous)
+			} else {
+				validator.operationIds[operationId] = operationPointer
+			}
+		}
+		validator.validateResponses(operation["responses"], joinPointer(operationPointer, "responses"))
+	}
+}
+
+// validateParameters checks a parameter list and returns the names of the declared path parameters.
+func (validator *documentValidator) validateParameters(value any, pointer string) map[string]bool {
+	declared := make(map[string]bool)
+	if value == nil {
+		return declared
+	}
+	parameters, ok := value.([]any)
+	if !ok {
+		validator.fail(pointer, "must be an array")
+		return declared
+	}
+	for i, parameterValue := range parameters {
+		parameterPointer := joinPointer(pointer, i)
+		parameter, ok := validator.object(parameterValue, parameterPointer)
+		if !ok {
+			continue
+		}
+		if ref, ok := parameter["$ref"].(string); ok {
+			// A referenced parameter is validated in components, only its location matters here
+			if target, ok := lookupPointer(validator.merged.document, ref); ok {
+				if target, ok := target.(map[string]any); ok && target["in"] == "path" {
+					declared[fmt.Sprintf("%v", target["name"])] = true
+				}
+			}
+			continue
+		}
+		validator.requireString(parameter, parameterPointer, "name")
+		location, _ := parameter["in"].(string)
+		if !parameterLocations[location] {
+			validator.fail(joinPointer(parameterPointer, "in"), "invalid parameter location %q", location)
+		}
+		if location == "path" {
+			declared[fmt.Sprintf("%v", parameter["name"])] = true
+			if parameter["required"] != true {
+				validator.fail(parameterPointer, "path parameter %v must be required", parameter["name"])
+			}
+		}
+		_, hasSchema := parameter["schema"]
+		_, hasContent := parameter["content"]
+		if hasSchema == hasContent {
+			validator.fail(parameterPointer, "parameter must have either schema or content")
+		}
+	}
+	return declared
+}
+
+func (validator *documentValidator) validateResponses(value any, pointer string) {
+	if value == nil {
+		validator.fail(pointer, "missing required field %q", "responses")
+		return
+	}
+	responses, ok := validator.object(value, pointer)
+	if !ok {
+		return
+	}
+	if len(responses) == 0 {
+		validator.fail(pointer, "must contain at least one response")
+	}
+	for _, code := range sortedKeys(responses) {
+		responsePointer := joinPointer(pointer, code)
+		if !responseCodePattern.MatchString(code) {
+			validator.fail(responsePointer, "invalid response code %q", code)
+		}
+		response, ok := validator.object(responses[code], responsePointer)
+		if !ok || response["$ref"] != nil {
+			continue
+		}
+		validator.requireString(response, responsePointer, "description")
+	}
+}
+
+// validateReferences reports every internal `$ref` that does not resolve.
+func (validator *documentValidator) validateReferences(value any, pointer string) {
+	switch node := value.(type) {
+	case map[string]any:
+		for _, key := range sortedKeys(node) {
+			if ref, ok := node[key].(string); key == "$ref" && ok {
+				if strings.HasPrefix(ref, "#") {
+					if _, exists := lookupPointer(validator.merged.document, ref); !exists {
+						validator.fail(joinPointer(pointer, key), "dangling reference %v", ref)
+					}
+				}
+				continue
+			}
+			validator.validateReferences(node[key], joinPointer(pointer, key))
+		}
+	case []any:
+		for i, child := range node {
+			validator.validateReferences(child, joinPointer(pointer, i))
+		}
+	}
+}
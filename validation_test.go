@@ -0,0 +1,125 @@
+package swagger_ring_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	swagger "github.com/usalko/swagger-ring"
+)
+
+const validSource = `
+openapi: 3.0.2
+info:
+  title: Users
+  version: 1.0.0
+paths:
+  /users/{id}:
+    get:
+      operationId: getUser
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        200:
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+components:
+  schemas:
+    User:
+      type: object
+`
+
+const invalidSource = `
+openapi: 3.0.2
+info:
+  title: Orders
+  version: 1.0.0
+paths:
+  /widgets:
+    get:
+      operationId: getUser
+      parameters:
+        - name: limit
+          in: body
+          schema:
+            type: integer
+    post:
+      responses:
+        201:
+          description: Created
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Order'
+`
+
+func TestValidation(t *testing.T) {
+	ctx := context.Background()
+
+	source := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/orders.yaml" {
+			fmt.Fprint(rw, invalidSource)
+			return
+		}
+		fmt.Fprint(rw, validSource)
+	}))
+	defer source.Close()
+
+	cfg := swagger.CreateConfig()
+	cfg.Path = "/api/v1/docs"
+	cfg.Docs = append(cfg.Docs, &swagger.DocPath{Path: source.URL + "/users.yaml"})
+
+	handler, err := swagger.New(ctx, http.NotFoundHandler(), cfg, "swagger-ring")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	report := getJSON[*swagger.ValidationReport](t, handler, "/api/v1/docs/validation")
+	if !report.Valid || len(report.Errors) != 0 {
+		t.Errorf("expected valid document, got %+v", report.Errors)
+	}
+
+	cfg.Docs = append(cfg.Docs, &swagger.DocPath{Path: source.URL + "/orders.yaml", Name: "orders"})
+	cfg.FailOnInvalid = true
+	handler, err = swagger.New(ctx, http.NotFoundHandler(), cfg, "swagger-ring")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	report = getJSON[*swagger.ValidationReport](t, handler, "/api/v1/docs/validation")
+	if report.Valid {
+		t.Error("expected invalid document")
+	}
+	expected := map[string]bool{
+		"/paths/~1widgets/get/operationId":                                          true,
+		"/paths/~1widgets/get/parameters/0/in":                                      true,
+		"/paths/~1widgets/get/responses":                                            true,
+		"/paths/~1widgets/post/responses/201/content/application~1json/schema/$ref": true,
+	}
+	for _, validationError := range report.Errors {
+		if !expected[validationError.Pointer] {
+			t.Errorf("unexpected error %+v", validationError)
+			continue
+		}
+		if validationError.Source != "orders" {
+			t.Errorf("expected error from orders source, got %v", validationError.Source)
+		}
+		delete(expected, validationError.Pointer)
+	}
+	for pointer := range expected {
+		t.Errorf("expected error at %v", pointer)
+	}
+
+	rw := httptest.NewRecorder()
+	handler.ServeHTTP(rw, httptest.NewRequest("GET", "/api/v1/docs/swagger.yaml", nil))
+	if rw.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d for invalid document, got %d", http.StatusInternalServerError, rw.Code)
+	}
+}